@@ -0,0 +1,48 @@
+{{/*
+Application name; Used for every resource name and the 'app' label. Defaults to the release name.
+*/}}
+{{- define "app-team.name" -}}
+{{- default .Release.Name .Values.nameOverride | trunc 63 | trimSuffix "-" }}
+{{- end }}
+
+{{/*
+Application container; Shared by the stable & canary Deployments. Called with a dict of 'root' (the chart context),
+'image' & 'tag'. The image may carry its own tag ('repository:tag') or digest; A non-empty 'tag' replaces it.
+*/}}
+{{- define "app-team.container" -}}
+{{- $root := .root -}}
+{{- $image := .image -}}
+{{- if .tag -}}
+{{- $image = printf "%s:%s" (regexReplaceAll "(@[^/]*|:[^/:]*)$" .image "") .tag -}}
+{{- end -}}
+- name: {{ include "app-team.name" $root }}
+  image: "{{ $image }}"
+  ports:
+  - containerPort: 8080
+    protocol: TCP
+  readinessProbe:
+    {{- toYaml $root.Values.probes.readiness | nindent 4 }}
+  livenessProbe:
+    {{- toYaml $root.Values.probes.liveness | nindent 4 }}
+  resources:
+    {{- toYaml $root.Values.resources | nindent 4 }}
+  securityContext:
+    {{- toYaml $root.Values.securityContext | nindent 4 }}
+  env:
+  - name: CUSTOMER
+    value: "{{ $root.Values.deployment.env.customer }}"
+  - name: COLOR_PRIMARY
+    value: "{{ $root.Values.deployment.env.color_primary }}"
+  - name: COLOR_SECONDARY
+    value: "{{ $root.Values.deployment.env.color_secondary }}"
+  - name: COLOR_BACKGROUND
+    value: "{{ $root.Values.deployment.env.color_background }}"
+  - name: LOCATION
+    value: "{{ $root.Values.deployment.env.location }}"
+  - name: PLATFORM
+    value: "{{ $root.Values.deployment.env.platform }}"
+  {{- range $name, $value := $root.Values.deployment.extraEnv }}
+  - name: {{ $name }}
+    value: {{ $value | quote }}
+  {{- end }}
+{{- end }}
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/compute"
+	k8s "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/core/v1"
+	helm "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/helm/v3"
+	metav1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/meta/v1"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// Directory of the Helm Charts shipped with this repository
+const localChartPath = "../apps/helm"
+
+// An Application in the Catalog; A Helm Chart deployed to its target regions and routed to by host & path.
+type application struct {
+	Name string `json:"name"`
+	// Chart Name; Loaded from 'chartPath' or fetched from the Helm Repository 'repo'
+	Chart     string `json:"chart"`
+	ChartPath string `json:"chartPath"`
+	Repo      string `json:"repo"`
+	Version   string `json:"version"`
+	Namespace string `json:"namespace"`
+	// Helm Values; Layered over the stack & region values, then the Application's region overrides (keyed by region)
+	Values       map[string]interface{}            `json:"values"`
+	RegionValues map[string]map[string]interface{} `json:"regionValues"`
+	// Target Regions; Every enabled region when empty
+	Regions []string `json:"regions"`
+	// Load Balancer Routing; The Domain (or any host) and every path when empty
+	Host string `json:"host"`
+	Path string `json:"path"`
+	// Kubernetes Service serving the Application (Used by the Multi-Cluster Gateway)
+	Service string `json:"service"`
+	Port    int    `json:"port"`
+	// Canary Deployment & its share of the traffic; No canary when empty
+	Canary *appCanaryConfig `json:"canary"`
+}
+
+// The Catalog deployed when 'applications' is not configured
+var defaultApplications = []*application{
+	{
+		Name:      "up-and-running",
+		Chart:     "app-team",
+		Namespace: "app-team",
+	},
+}
+
+// Application Values set by the program for every Application; The stack-wide 'appValues' override them
+var appDefaultValues = map[string]interface{}{
+	"deployment": map[string]interface{}{
+		"env": map[string]interface{}{
+			"customer":         "Pulumi Developers",
+			"color_primary":    "#805ac3",
+			"color_secondary":  "#4d5bd9",
+			"color_background": "#f7bf2a",
+		},
+	},
+}
+
+// Application Values shared by every Application; Stack-wide values with per-region overrides (keyed by region)
+type appValuesConfig struct {
+	Values  map[string]interface{}            `json:"values"`
+	Regions map[string]map[string]interface{} `json:"regions"`
+}
+
+// Function - Read & Validate the Application Values from the 'appValues' configuration
+func loadAppValuesConfig(cfg *config.Config) (*appValuesConfig, error) {
+	appValues := &appValuesConfig{}
+	if err := cfg.GetObject("appValues", appValues); err != nil {
+		return nil, fmt.Errorf("[CONFIGURATION] - App Values: %w", err)
+	}
+	for region := range appValues.Regions {
+		if !isCloudRegion(region) {
+			return nil, fmt.Errorf("[CONFIGURATION] - App Values: values for unknown region '%s'", region)
+		}
+		fmt.Printf("[CONFIGURATION] - App Values: Region %s overrides the stack-wide application values.\n", region)
+	}
+	return appValues, nil
+}
+
+// Function - The Application Values for a region; Region overrides layered on the stack-wide values
+func (c *appValuesConfig) forRegion(region string) map[string]interface{} {
+	return mergeValues(c.Values, c.Regions[region])
+}
+
+// Default Application Scaling
+var appScalingDefaults = scalingConfig{
+	MinReplicas:          2,
+	MaxReplicas:          5,
+	TargetCPUUtilization: 70,
+	Requests: map[string]string{
+		"cpu":    "100m",
+		"memory": "128Mi",
+	},
+	Limits: map[string]string{
+		"cpu":    "500m",
+		"memory": "256Mi",
+	},
+	PDBMinAvailable: pulumi.IntRef(1),
+	ZoneSpread:      pulumi.BoolRef(true),
+}
+
+// Function - Read & Validate the Application Scaling configuration
+func loadAppScalingConfig(cfg *config.Config) (*regionalScalingConfig, error) {
+	return loadRegionalScalingConfig(cfg, "appScaling", "Application Scaling", appScalingDefaults)
+}
+
+// Function - Helm values for the replicas, autoscaling, resources, disruption budget & zone spread of the 'app-team' chart;
+// Autoscaling is enabled when maxReplicas is greater than minReplicas.
+func appScalingHelmValues(s scalingConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"replicaCount": s.MinReplicas,
+		"autoscaling": map[string]interface{}{
+			"enabled":                        s.MaxReplicas > s.MinReplicas,
+			"minReplicas":                    s.MinReplicas,
+			"maxReplicas":                    s.MaxReplicas,
+			"targetCPUUtilizationPercentage": s.TargetCPUUtilization,
+		},
+		"resources": map[string]interface{}{
+			"requests": stringMapValues(s.Requests),
+			"limits":   stringMapValues(s.Limits),
+		},
+		"podDisruptionBudget": map[string]interface{}{
+			"minAvailable": *s.PDBMinAvailable,
+		},
+		"topologySpread": map[string]interface{}{
+			"enabled": *s.ZoneSpread,
+		},
+	}
+}
+
+// Function - Read & Validate the Application Catalog from the 'applications' configuration
+func loadApplications(cfg *config.Config) ([]*application, error) {
+	applications := []*application{}
+	if err := cfg.GetObject("applications", &applications); err != nil {
+		return nil, fmt.Errorf("[CONFIGURATION] - Applications: %w", err)
+	}
+	if len(applications) == 0 {
+		applications = defaultApplications
+	}
+
+	seenNames := map[string]bool{}
+	seenRoutes := map[string]string{}
+	for _, app := range applications {
+		if app.Name == "" {
+			return nil, fmt.Errorf("[CONFIGURATION] - Applications: every application must have a 'name'")
+		}
+		if seenNames[app.Name] {
+			return nil, fmt.Errorf("[CONFIGURATION] - Applications: application '%s' is declared more than once", app.Name)
+		}
+		seenNames[app.Name] = true
+		if app.Chart == "" {
+			app.Chart = "app-team"
+		}
+		if app.Repo == "" && app.ChartPath == "" {
+			app.ChartPath = localChartPath
+		}
+		if app.Repo != "" && app.ChartPath != "" {
+			return nil, fmt.Errorf("[CONFIGURATION] - Applications: '%s' must set either 'chartPath' or 'repo', not both", app.Name)
+		}
+		if app.Version == "" && app.Repo == "" {
+			app.Version = "0.1.0"
+		}
+		if app.Namespace == "" {
+			app.Namespace = app.Name
+		}
+		if app.Service == "" {
+			app.Service = app.Name
+		}
+		if app.Port == 0 {
+			app.Port = 80
+		}
+		if app.Path == "" {
+			app.Path = "/*"
+		}
+		if !strings.HasPrefix(app.Path, "/") || !strings.HasSuffix(app.Path, "/*") {
+			return nil, fmt.Errorf("[CONFIGURATION] - Applications: '%s' path '%s' must start with '/' and end with '/*'", app.Name, app.Path)
+		}
+		// Google-managed SSL Certificates cannot hold wildcard hosts
+		if strings.Contains(app.Host, "*") {
+			return nil, fmt.Errorf("[CONFIGURATION] - Applications: '%s' host '%s' must not be a wildcard", app.Name, app.Host)
+		}
+		route := fmt.Sprintf("%s%s", app.Host, app.Path)
+		if other, ok := seenRoutes[route]; ok {
+			return nil, fmt.Errorf("[CONFIGURATION] - Applications: '%s' and '%s' are both routed to '%s'", other, app.Name, route)
+		}
+		seenRoutes[route] = app.Name
+		for _, region := range app.Regions {
+			if !isCloudRegion(region) {
+				return nil, fmt.Errorf("[CONFIGURATION] - Applications: '%s' targets unknown region '%s'", app.Name, region)
+			}
+		}
+		for region := range app.RegionValues {
+			if !isCloudRegion(region) {
+				return nil, fmt.Errorf("[CONFIGURATION] - Applications: '%s' has values for unknown region '%s'", app.Name, region)
+			}
+			fmt.Printf("[CONFIGURATION] - Application: '%s' - Region %s has its own values.\n", app.Name, region)
+		}
+		if app.Canary != nil {
+			if err := app.Canary.validate(app.Name); err != nil {
+				return nil, err
+			}
+		}
+		fmt.Printf("[CONFIGURATION] - Application: '%s' - Chart '%s' into namespace '%s', routed from '%s'.\n", app.Name, app.Chart, app.Namespace, route)
+	}
+	return applications, nil
+}
+
+// Function - Whether a region is one of the Cloud Regions
+func isCloudRegion(region string) bool {
+	for _, cloudRegion := range CloudRegions {
+		if cloudRegion.Region == region {
+			return true
+		}
+	}
+	return false
+}
+
+// Function - Whether the Application is deployed to a region
+func (a *application) deployedTo(region string) bool {
+	if len(a.Regions) == 0 {
+		return true
+	}
+	for _, target := range a.Regions {
+		if target == region {
+			return true
+		}
+	}
+	return false
+}
+
+// Function - The Istio VirtualService host for the Application
+func (a *application) ingressHost() string {
+	if a.Host == "" {
+		return "*"
+	}
+	return a.Host
+}
+
+// Function - The URI prefix matched for the Application, eg. '/shop/*' matches the prefix '/shop/'
+func (a *application) pathPrefix() string {
+	return strings.TrimSuffix(a.Path, "*")
+}
+
+// Function - Deploy the Application's Helm Chart into a regional cluster; 'values' are the values for the region,
+// the Application's canary, its own values and then its overrides for the region are layered on top.
+func (a *application) deploy(ctx *pulumi.Context, resourceNamePrefix string, cloudRegion cloudRegion, values map[string]interface{}, opts ...pulumi.ResourceOption) (*helm.Chart, error) {
+	canaryValues := map[string]interface{}{}
+	if a.Canary != nil {
+		canaryValues = a.Canary.helmValues(cloudRegion.Region)
+	}
+	values = mergeValues(values, map[string]interface{}{
+		"nameOverride": a.Name,
+		"ingress": map[string]interface{}{
+			"host":     a.ingressHost(),
+			"prefix":   a.pathPrefix(),
+			"delegate": true,
+		},
+	}, canaryValues, a.Values, a.RegionValues[cloudRegion.Region])
+	chartArgs := helm.ChartArgs{
+		Chart:          pulumi.String(a.Chart),
+		ResourcePrefix: cloudRegion.Id,
+		Namespace:      pulumi.String(a.Namespace),
+		Values:         pulumi.ToMap(values),
+	}
+	if a.Version != "" {
+		chartArgs.Version = pulumi.String(a.Version)
+	}
+	if a.Repo != "" {
+		chartArgs.FetchArgs = &helm.FetchArgs{
+			Repo: pulumi.String(a.Repo),
+		}
+	} else {
+		chartArgs.Path = pulumi.String(a.ChartPath)
+	}
+	resourceName := fmt.Sprintf("%s-app-%s-%s", resourceNamePrefix, a.Name, cloudRegion.Region)
+	helmApp, err := helm.NewChart(ctx, resourceName, chartArgs, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Export the IP Address of the per-cluster External Service (For debugging only)
+	if externalService, ok := values["externalService"].(map[string]interface{}); ok && externalService["enabled"] == true {
+		fmt.Printf("[ INFORMATION ] - Cloud Region: %s - Application '%s' has an External Service that bypasses the Global Load Balancer\n", cloudRegion.Region, a.Name)
+		externalServiceIp := helmApp.GetResource("v1/Service", fmt.Sprintf("%s-external", values["nameOverride"]), a.Namespace).ApplyT(func(r interface{}) pulumi.StringPtrOutput {
+			k8sService, ok := r.(*k8s.Service)
+			if !ok {
+				return pulumi.StringPtr("").ToStringPtrOutput()
+			}
+			return k8sService.Status.LoadBalancer().Ingress().Index(pulumi.Int(0)).Ip()
+		}).(pulumi.StringPtrOutput)
+		ctx.Export(fmt.Sprintf("%s-app-%s-external-ip-%s", resourceNamePrefix, a.Name, cloudRegion.Region), externalServiceIp)
+	}
+	return helmApp, nil
+}
+
+// Function - Create a Namespace in a regional cluster unless it already exists in 'namespaces'
+func getOrCreateNamespace(ctx *pulumi.Context, resourceNamePrefix string, region string, name string, namespaces map[string]*k8s.Namespace, labels pulumi.StringMap, opts ...pulumi.ResourceOption) (*k8s.Namespace, error) {
+	if k8sNamespace, ok := namespaces[name]; ok {
+		return k8sNamespace, nil
+	}
+	resourceName := fmt.Sprintf("%s-k8s-ns-%s-%s", resourceNamePrefix, name, region)
+	k8sNamespace, err := k8s.NewNamespace(ctx, resourceName, &k8s.NamespaceArgs{
+		Metadata: &metav1.ObjectMetaArgs{
+			Name:   pulumi.String(name),
+			Labels: labels,
+		},
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	namespaces[name] = k8sNamespace
+	return k8sNamespace, nil
+}
+
+// Function - The Namespace names of a regional cluster (Sorted)
+func namespaceNames(namespaces map[string]*k8s.Namespace) []string {
+	names := make([]string, 0, len(namespaces))
+	for name := range namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Function - The Namespaces of a regional cluster as Resources (Sorted by name); Used with pulumi.DependsOn
+func namespaceResources(namespaces map[string]*k8s.Namespace) []pulumi.Resource {
+	resources := []pulumi.Resource{}
+	for _, name := range namespaceNames(namespaces) {
+		resources = append(resources, namespaces[name])
+	}
+	return resources
+}
+
+// Function - Global Load Balancer URL Map Host Rules & Path Matchers for the Application Catalog. Every path is
+// served by the Istio Ingress Gateway backend, which routes to the Application with its VirtualService.
+func urlMapRouting(applications []*application, defaultHost string, backendService pulumi.StringInput) (compute.URLMapHostRuleArray, compute.URLMapPathMatcherArray) {
+	hosts := []string{}
+	pathRules := map[string]compute.URLMapPathMatcherPathRuleArray{}
+	for _, app := range applications {
+		host := app.Host
+		if host == "" {
+			host = defaultHost
+		}
+		if _, ok := pathRules[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		pathRules[host] = append(pathRules[host], &compute.URLMapPathMatcherPathRuleArgs{
+			Paths: pulumi.StringArray{
+				pulumi.String(app.Path),
+			},
+			Service: backendService,
+		})
+	}
+
+	hostRules := compute.URLMapHostRuleArray{}
+	pathMatchers := compute.URLMapPathMatcherArray{}
+	for i, host := range hosts {
+		pathMatcher := fmt.Sprintf("apps-%d", i+1)
+		hostRules = append(hostRules, &compute.URLMapHostRuleArgs{
+			Hosts: pulumi.StringArray{
+				pulumi.String(host),
+			},
+			PathMatcher: pulumi.String(pathMatcher),
+			Description: pulumi.String(fmt.Sprintf("Applications - %s", host)),
+		})
+		pathMatchers = append(pathMatchers, &compute.URLMapPathMatcherArgs{
+			Name:           pulumi.String(pathMatcher),
+			DefaultService: backendService,
+			PathRules:      pathRules[host],
+		})
+	}
+	return hostRules, pathMatchers
+}
+
+// Function - The hosts served by the Load Balancer; The Domain followed by every distinct Application host
+func applicationHosts(applications []*application, domain string) []string {
+	hosts := []string{}
+	seen := map[string]bool{"": true}
+	candidates := []string{domain}
+	for _, app := range applications {
+		candidates = append(candidates, app.Host)
+	}
+	for _, host := range candidates {
+		if !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// Function - Convert a String Map into Helm Values
+func stringMapValues(m map[string]string) map[string]interface{} {
+	values := map[string]interface{}{}
+	for key, value := range m {
+		values[key] = value
+	}
+	return values
+}
+
+// Function - Deep Merge Helm Values; Keys in later maps override earlier ones, nested maps are merged
+func mergeValues(maps ...map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for _, m := range maps {
+		for key, value := range m {
+			existing, existingIsMap := merged[key].(map[string]interface{})
+			override, overrideIsMap := value.(map[string]interface{})
+			if existingIsMap && overrideIsMap {
+				merged[key] = mergeValues(existing, override)
+				continue
+			}
+			merged[key] = value
+		}
+	}
+	return merged
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/projects"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/serviceaccount"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// AutoNeg Controller Image Tag installed when none is configured
+const autonegDefaultImageTag = "v1.0.0"
+
+// AutoNeg Controller Region Overrides; Used to roll a new controller version out one region at a time.
+type autonegRegionConfig struct {
+	ImageTag string `json:"imageTag"`
+}
+
+// AutoNeg Controller Configuration; Passed to the 'cluster-ops' Helm Chart
+type autonegConfig struct {
+	Namespace      string                         `json:"namespace"`
+	ServiceAccount string                         `json:"serviceAccount"`
+	ImageTag       string                         `json:"imageTag"`
+	Replicas       int                            `json:"replicas"`
+	LogLevel       string                         `json:"logLevel"`
+	Requests       map[string]string              `json:"requests"`
+	Limits         map[string]string              `json:"limits"`
+	Regions        map[string]autonegRegionConfig `json:"regions"`
+}
+
+// Function - Read & Validate the AutoNeg Controller configuration
+func loadAutonegConfig(cfg *config.Config) (*autonegConfig, error) {
+	autoneg := &autonegConfig{}
+	if err := cfg.GetObject("autoneg", autoneg); err != nil {
+		return nil, fmt.Errorf("[CONFIGURATION] - AutoNeg: %w", err)
+	}
+	if autoneg.Namespace == "" {
+		autoneg.Namespace = "autoneg-system"
+	}
+	if autoneg.ServiceAccount == "" {
+		autoneg.ServiceAccount = "autoneg-controller-manager"
+	}
+	if autoneg.ImageTag == "" {
+		autoneg.ImageTag = autonegDefaultImageTag
+	}
+	if autoneg.Replicas == 0 {
+		autoneg.Replicas = 1
+	}
+	if autoneg.LogLevel == "" {
+		autoneg.LogLevel = "info"
+	}
+	switch autoneg.LogLevel {
+	case "debug", "info", "error":
+	default:
+		return nil, fmt.Errorf("[CONFIGURATION] - AutoNeg: logLevel '%s' must be one of 'debug', 'info' or 'error'", autoneg.LogLevel)
+	}
+	autoneg.Requests = mergeStringMaps(map[string]string{"cpu": "100m", "memory": "20Mi"}, autoneg.Requests)
+	autoneg.Limits = mergeStringMaps(map[string]string{"cpu": "100m", "memory": "30Mi"}, autoneg.Limits)
+	fmt.Printf("[CONFIGURATION] - AutoNeg: Controller %s will run as '%s/%s'.\n", autoneg.ImageTag, autoneg.Namespace, autoneg.ServiceAccount)
+	return autoneg, nil
+}
+
+// Function - The Workload Identity member for the AutoNeg Kubernetes Service Account
+func (c *autonegConfig) workloadIdentityMember(gcpProjectId string) string {
+	return fmt.Sprintf("serviceAccount:%s.svc.id.goog[%s/%s]", gcpProjectId, c.Namespace, c.ServiceAccount)
+}
+
+// Function - Helm values for the 'autoneg' section of the 'cluster-ops' chart in a region
+func (c *autonegConfig) helmValues(region string, gcpServiceAccountEmail pulumi.StringOutput) pulumi.Map {
+	imageTag := c.ImageTag
+	if override := c.Regions[region].ImageTag; override != "" {
+		imageTag = override
+	}
+	return pulumi.Map{
+		"namespace": pulumi.String(c.Namespace),
+		"replicas":  pulumi.Int(c.Replicas),
+		"logLevel":  pulumi.String(c.LogLevel),
+		"image": pulumi.Map{
+			"tag": pulumi.String(imageTag),
+		},
+		"resources": pulumi.Map{
+			"requests": pulumi.ToStringMap(c.Requests),
+			"limits":   pulumi.ToStringMap(c.Limits),
+		},
+		"serviceAccount": pulumi.Map{
+			"name": pulumi.String(c.ServiceAccount),
+			"annotations": pulumi.Map{
+				"iam.gke.io/gcp-service-account": gcpServiceAccountEmail,
+			},
+		},
+	}
+}
+
+// Function - Create the AutoNeg Google Cloud Service Account and grant it the Custom Role it needs to
+// register the Istio Ingress Gateway NEGs with the Global Load Balancer Backend Service.
+func createAutonegServiceAccount(ctx *pulumi.Context, names *stackNamer, gcpProjectId string, resourceNamePrefix string, opts ...pulumi.ResourceOption) (*serviceaccount.Account, error) {
+	// Create Custom IAM Role that will be used by the AutoNeg Kubernetes Deployment
+	// This Role allows the AutoNeg CRD to link the Istio Ingress Gateway Service Ip to Load Balancer NEGs
+	resourceName := fmt.Sprintf("%s-iam-custom-role-autoneg", resourceNamePrefix)
+	gcpIAMRoleAutoNeg, err := projects.NewIAMCustomRole(ctx, resourceName, &projects.IAMCustomRoleArgs{
+		Project:     pulumi.String(gcpProjectId),
+		Description: pulumi.String("Custom IAM Role - GKE AutoNeg"),
+		Permissions: pulumi.StringArray{
+			pulumi.String("compute.backendServices.get"),
+			pulumi.String("compute.backendServices.update"),
+			pulumi.String("compute.regionBackendServices.get"),
+			pulumi.String("compute.regionBackendServices.update"),
+			pulumi.String("compute.networkEndpointGroups.use"),
+			pulumi.String("compute.healthChecks.useReadOnly"),
+			pulumi.String("compute.regionHealthChecks.useReadOnly"),
+		},
+
+		RoleId: names.CustomRoleId("iam_role_autoneg_system"),
+		Title:  pulumi.String("GKE at Scale - AutoNEG"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create AutoNeg Service Account
+	resourceName = fmt.Sprintf("%s-service-account-autoneg", resourceNamePrefix)
+	gcpServiceAccountAutoNeg, err := serviceaccount.NewAccount(ctx, resourceName, &serviceaccount.AccountArgs{
+		Project:     pulumi.String(gcpProjectId),
+		AccountId:   names.ServiceAccountId("autoneg-system"),
+		DisplayName: pulumi.String("GKE at Scale - AutoNEG Service Account"),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Grant the AutoNeg Service Account the Custom Role (Non-Authoritative; other members of the Role are kept).
+	resourceName = fmt.Sprintf("%s-iam-member-autoneg", resourceNamePrefix)
+	_, err = projects.NewIAMMember(ctx, resourceName, &projects.IAMMemberArgs{
+		Project: pulumi.String(gcpProjectId),
+		Role:    gcpIAMRoleAutoNeg.Name,
+		Member:  pulumi.Sprintf("serviceAccount:%s", gcpServiceAccountAutoNeg.Email),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcpServiceAccountAutoNeg, nil
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	k8s "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/core/v1"
+	metav1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/meta/v1"
+	networkingv1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/networking/v1"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// Pod Security Admission Levels
+const (
+	podSecurityPrivileged = "privileged"
+	podSecurityBaseline   = "baseline"
+	podSecurityRestricted = "restricted"
+)
+
+// Google Cloud Load Balancer Proxy & Health Check Ranges
+var loadBalancerSourceRanges = []string{"35.191.0.0/16", "130.211.0.0/22"}
+
+// GKE Metadata Server; Serves Workload Identity tokens on 169.254.169.252:988, reached through 169.254.169.254:80
+const (
+	metadataServerCidr   = "169.254.169.252/32"
+	metadataServerPort   = 988
+	metadataEndpointCidr = "169.254.169.254/32"
+	metadataEndpointPort = 80
+)
+
+// Application Namespace Baseline; Unset (zero) values fall back to the stack-wide setting, then the defaults.
+type namespaceBaselineConfig struct {
+	// Deny all ingress & egress except DNS, istio-system, the Ingress Gateway and 'egressCidrs'
+	DefaultDeny *bool `json:"defaultDeny"`
+	// Pod Security Admission level enforced, audited & warned on; 'privileged', 'baseline' or 'restricted'
+	PodSecurity string `json:"podSecurity"`
+	// Additional destinations the pods may reach
+	EgressCidrs []string `json:"egressCidrs"`
+	// ResourceQuota hard limits
+	Quota map[string]string `json:"quota"`
+	// LimitRange default container limits & requests
+	Limits   map[string]string `json:"limits"`
+	Requests map[string]string `json:"requests"`
+}
+
+// Application Namespace Baselines; Stack-wide baseline with per-namespace overrides (keyed by namespace)
+type namespaceBaselinesConfig struct {
+	namespaceBaselineConfig
+	Namespaces map[string]namespaceBaselineConfig `json:"namespaces"`
+}
+
+// Traffic the Application Namespaces must allow besides DNS, istio-system & the Ingress Gateway
+type namespaceTraffic struct {
+	// Load Balancer proxies & health checks reach the pods directly (Multi-Cluster Gateway)
+	LoadBalancerToPods bool
+	// Sidecars reach the other clusters through their East-West Gateways (Multi-Cluster Mesh)
+	EastWestGateways bool
+	// Sidecars reach the Google managed control plane (Managed Mesh)
+	ManagedControlPlane bool
+	// Pods fetch Workload Identity tokens from the GKE Metadata Server (App Identities or Managed Mesh)
+	MetadataServer bool
+}
+
+// Default Application Namespace Baseline
+var namespaceBaselineDefaults = namespaceBaselineConfig{
+	DefaultDeny: pulumi.BoolRef(true),
+	PodSecurity: podSecurityRestricted,
+	Quota: map[string]string{
+		"requests.cpu":    "8",
+		"requests.memory": "16Gi",
+		"limits.cpu":      "32",
+		"limits.memory":   "64Gi",
+		"pods":            "100",
+	},
+	Limits: map[string]string{
+		"cpu":    "500m",
+		"memory": "256Mi",
+	},
+	Requests: map[string]string{
+		"cpu":    "100m",
+		"memory": "128Mi",
+	},
+}
+
+// Function - Read & Validate the Application Namespace Baselines from the 'namespaceBaseline' configuration
+func loadNamespaceBaselineConfig(cfg *config.Config) (*namespaceBaselinesConfig, error) {
+	baseline := &namespaceBaselinesConfig{}
+	if err := cfg.GetObject("namespaceBaseline", baseline); err != nil {
+		return nil, fmt.Errorf("[CONFIGURATION] - Namespace Baseline: %w", err)
+	}
+	baseline.namespaceBaselineConfig = baseline.namespaceBaselineConfig.merge(namespaceBaselineDefaults)
+	if err := baseline.namespaceBaselineConfig.validate("stack"); err != nil {
+		return nil, err
+	}
+	fmt.Printf("[CONFIGURATION] - Namespace Baseline: Default Deny NetworkPolicies %t; Pod Security '%s'.\n", *baseline.DefaultDeny, baseline.PodSecurity)
+	for namespace := range baseline.Namespaces {
+		namespaceBaseline := baseline.forNamespace(namespace)
+		if err := namespaceBaseline.validate(namespace); err != nil {
+			return nil, err
+		}
+		fmt.Printf("[CONFIGURATION] - Namespace Baseline: Namespace '%s' - Default Deny NetworkPolicies %t; Pod Security '%s'.\n", namespace, *namespaceBaseline.DefaultDeny, namespaceBaseline.PodSecurity)
+	}
+	return baseline, nil
+}
+
+// Function - Check every namespace override targets a namespace the program creates
+func (c *namespaceBaselinesConfig) checkNamespaces(namespaces []string) error {
+	known := map[string]bool{}
+	for _, namespace := range namespaces {
+		known[namespace] = true
+	}
+	for namespace := range c.Namespaces {
+		if !known[namespace] {
+			return fmt.Errorf("[CONFIGURATION] - Namespace Baseline: baseline for unknown namespace '%s'", namespace)
+		}
+	}
+	return nil
+}
+
+// Function - The Baseline for a namespace; Namespace overrides layered on the stack-wide baseline
+func (c *namespaceBaselinesConfig) forNamespace(namespace string) namespaceBaselineConfig {
+	return c.Namespaces[namespace].merge(c.namespaceBaselineConfig)
+}
+
+// Function - Whether any namespace enforces a Pod Security level that rejects the privileged Istio init container;
+// The in-cluster Istio install then needs the Istio CNI plugin.
+func (c *namespaceBaselinesConfig) requiresIstioCNI() bool {
+	if c.PodSecurity != podSecurityPrivileged {
+		return true
+	}
+	for namespace := range c.Namespaces {
+		if c.forNamespace(namespace).PodSecurity != podSecurityPrivileged {
+			return true
+		}
+	}
+	return false
+}
+
+// Function - Fill any unset values from the given base
+func (b namespaceBaselineConfig) merge(base namespaceBaselineConfig) namespaceBaselineConfig {
+	if b.DefaultDeny == nil {
+		b.DefaultDeny = base.DefaultDeny
+	}
+	if b.PodSecurity == "" {
+		b.PodSecurity = base.PodSecurity
+	}
+	if b.EgressCidrs == nil {
+		b.EgressCidrs = base.EgressCidrs
+	}
+	b.Quota = mergeStringMaps(base.Quota, b.Quota)
+	b.Limits = mergeStringMaps(base.Limits, b.Limits)
+	b.Requests = mergeStringMaps(base.Requests, b.Requests)
+	return b
+}
+
+// Function - Check the Namespace Baseline is consistent
+func (b namespaceBaselineConfig) validate(scope string) error {
+	switch b.PodSecurity {
+	case podSecurityPrivileged, podSecurityBaseline, podSecurityRestricted:
+	default:
+		return fmt.Errorf("[CONFIGURATION] - Namespace Baseline (%s): podSecurity '%s' must be '%s', '%s' or '%s'", scope, b.PodSecurity, podSecurityPrivileged, podSecurityBaseline, podSecurityRestricted)
+	}
+	for _, cidr := range b.EgressCidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("[CONFIGURATION] - Namespace Baseline (%s): egressCidrs '%s' must be a CIDR range, eg. '10.10.0.0/16'", scope, cidr)
+		}
+	}
+	return nil
+}
+
+// Function - The Pod Security Admission labels of a namespace
+func (b namespaceBaselineConfig) podSecurityLabels() map[string]string {
+	return map[string]string{
+		"pod-security.kubernetes.io/enforce": b.PodSecurity,
+		"pod-security.kubernetes.io/audit":   b.PodSecurity,
+		"pod-security.kubernetes.io/warn":    b.PodSecurity,
+	}
+}
+
+// Function - Apply the Baseline (NetworkPolicies, ResourceQuota & LimitRange) to every Application Namespace of a cluster.
+// The Ingress Gateway runs in its own application namespace so it may receive traffic from anywhere and reach the other namespaces.
+func createNamespaceBaselines(ctx *pulumi.Context, resourceNamePrefix string, region string, baseline *namespaceBaselinesConfig, traffic namespaceTraffic, gatewayNamespace string, appNamespaces map[string]*k8s.Namespace, opts ...pulumi.ResourceOption) ([]pulumi.Resource, error) {
+	resources := []pulumi.Resource{}
+	namespaces := namespaceNames(appNamespaces)
+	for _, namespace := range namespaces {
+		namespaceBaseline := baseline.forNamespace(namespace)
+		appNamespace := appNamespaces[namespace].Metadata.Name().Elem()
+		resourceNameSuffix := region
+		if namespace != gatewayNamespace {
+			resourceNameSuffix = fmt.Sprintf("%s-%s", namespace, region)
+		}
+
+		// ResourceQuota
+		resourceName := fmt.Sprintf("%s-k8s-quota-%s", resourceNamePrefix, resourceNameSuffix)
+		k8sResourceQuota, err := k8s.NewResourceQuota(ctx, resourceName, &k8s.ResourceQuotaArgs{
+			Metadata: &metav1.ObjectMetaArgs{
+				Name:      pulumi.String("baseline"),
+				Namespace: appNamespace,
+			},
+			Spec: &k8s.ResourceQuotaSpecArgs{
+				Hard: pulumi.ToStringMap(namespaceBaseline.Quota),
+			},
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		// LimitRange; Default limits & requests for containers that do not set their own
+		resourceName = fmt.Sprintf("%s-k8s-limits-%s", resourceNamePrefix, resourceNameSuffix)
+		k8sLimitRange, err := k8s.NewLimitRange(ctx, resourceName, &k8s.LimitRangeArgs{
+			Metadata: &metav1.ObjectMetaArgs{
+				Name:      pulumi.String("baseline"),
+				Namespace: appNamespace,
+			},
+			Spec: &k8s.LimitRangeSpecArgs{
+				Limits: k8s.LimitRangeItemArray{
+					&k8s.LimitRangeItemArgs{
+						Type:           pulumi.String("Container"),
+						Default:        pulumi.ToStringMap(namespaceBaseline.Limits),
+						DefaultRequest: pulumi.ToStringMap(namespaceBaseline.Requests),
+					},
+				},
+			},
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, k8sResourceQuota, k8sLimitRange)
+
+		if !*namespaceBaseline.DefaultDeny {
+			continue
+		}
+
+		policies := map[string]*networkingv1.NetworkPolicySpecArgs{
+			// Default Deny; Selects every pod with no allowed ingress or egress
+			"default-deny": {
+				PodSelector: &metav1.LabelSelectorArgs{},
+				PolicyTypes: pulumi.ToStringArray([]string{"Ingress", "Egress"}),
+			},
+			// Allow DNS lookups against kube-dns
+			"allow-dns": {
+				PodSelector: &metav1.LabelSelectorArgs{},
+				PolicyTypes: pulumi.ToStringArray([]string{"Egress"}),
+				Egress: networkingv1.NetworkPolicyEgressRuleArray{
+					&networkingv1.NetworkPolicyEgressRuleArgs{
+						To: networkingv1.NetworkPolicyPeerArray{
+							namespacePeer("kube-system"),
+						},
+						Ports: networkingv1.NetworkPolicyPortArray{
+							&networkingv1.NetworkPolicyPortArgs{Protocol: pulumi.String("UDP"), Port: pulumi.Int(53)},
+							&networkingv1.NetworkPolicyPortArgs{Protocol: pulumi.String("TCP"), Port: pulumi.Int(53)},
+						},
+					},
+				},
+			},
+			// Allow the Istio Control Plane (and East-West Gateway) to & from the sidecars
+			"allow-istio-system": {
+				PodSelector: &metav1.LabelSelectorArgs{},
+				PolicyTypes: pulumi.ToStringArray([]string{"Ingress", "Egress"}),
+				Ingress: networkingv1.NetworkPolicyIngressRuleArray{
+					&networkingv1.NetworkPolicyIngressRuleArgs{
+						From: networkingv1.NetworkPolicyPeerArray{namespacePeer("istio-system")},
+					},
+				},
+				Egress: networkingv1.NetworkPolicyEgressRuleArray{
+					&networkingv1.NetworkPolicyEgressRuleArgs{
+						To: networkingv1.NetworkPolicyPeerArray{namespacePeer("istio-system")},
+					},
+				},
+			},
+			// Allow the Ingress Gateway to reach the Application pods
+			"allow-from-ingress-gateway": {
+				PodSelector: &metav1.LabelSelectorArgs{},
+				PolicyTypes: pulumi.ToStringArray([]string{"Ingress"}),
+				Ingress: networkingv1.NetworkPolicyIngressRuleArray{
+					&networkingv1.NetworkPolicyIngressRuleArgs{
+						From: networkingv1.NetworkPolicyPeerArray{
+							&networkingv1.NetworkPolicyPeerArgs{
+								NamespaceSelector: namespaceSelector(gatewayNamespace),
+								PodSelector:       ingressGatewaySelector(),
+							},
+						},
+					},
+				},
+			},
+		}
+
+		// The Ingress Gateway receives traffic from the Load Balancer & reaches every Application namespace
+		if namespace == gatewayNamespace {
+			policies["allow-ingress-gateway"] = &networkingv1.NetworkPolicySpecArgs{
+				PodSelector: ingressGatewaySelector(),
+				PolicyTypes: pulumi.ToStringArray([]string{"Ingress", "Egress"}),
+				Ingress: networkingv1.NetworkPolicyIngressRuleArray{
+					&networkingv1.NetworkPolicyIngressRuleArgs{},
+				},
+				Egress: networkingv1.NetworkPolicyEgressRuleArray{
+					&networkingv1.NetworkPolicyEgressRuleArgs{
+						To: networkingv1.NetworkPolicyPeerArray{
+							&networkingv1.NetworkPolicyPeerArgs{
+								NamespaceSelector: &metav1.LabelSelectorArgs{
+									MatchExpressions: metav1.LabelSelectorRequirementArray{
+										&metav1.LabelSelectorRequirementArgs{
+											Key:      pulumi.String("kubernetes.io/metadata.name"),
+											Operator: pulumi.String("In"),
+											Values:   pulumi.ToStringArray(namespaces),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+
+		// The Load Balancer reaches the Application pods directly
+		if traffic.LoadBalancerToPods {
+			policies["allow-from-load-balancer"] = &networkingv1.NetworkPolicySpecArgs{
+				PodSelector: &metav1.LabelSelectorArgs{},
+				PolicyTypes: pulumi.ToStringArray([]string{"Ingress"}),
+				Ingress: networkingv1.NetworkPolicyIngressRuleArray{
+					&networkingv1.NetworkPolicyIngressRuleArgs{
+						From: ipBlockPeers(loadBalancerSourceRanges),
+					},
+				},
+			}
+		}
+
+		// The sidecars reach the East-West Gateways of the other clusters (mTLS on 15443)
+		if traffic.EastWestGateways {
+			policies["allow-east-west-gateways"] = &networkingv1.NetworkPolicySpecArgs{
+				PodSelector: &metav1.LabelSelectorArgs{},
+				PolicyTypes: pulumi.ToStringArray([]string{"Egress"}),
+				Egress: networkingv1.NetworkPolicyEgressRuleArray{
+					&networkingv1.NetworkPolicyEgressRuleArgs{
+						Ports: networkingv1.NetworkPolicyPortArray{
+							&networkingv1.NetworkPolicyPortArgs{Protocol: pulumi.String("TCP"), Port: pulumi.Int(15443)},
+						},
+					},
+				},
+			}
+		}
+
+		// The sidecars reach the Google managed control plane over HTTPS
+		if traffic.ManagedControlPlane {
+			policies["allow-managed-control-plane"] = &networkingv1.NetworkPolicySpecArgs{
+				PodSelector: &metav1.LabelSelectorArgs{},
+				PolicyTypes: pulumi.ToStringArray([]string{"Egress"}),
+				Egress: networkingv1.NetworkPolicyEgressRuleArray{
+					&networkingv1.NetworkPolicyEgressRuleArgs{
+						Ports: networkingv1.NetworkPolicyPortArray{
+							&networkingv1.NetworkPolicyPortArgs{Protocol: pulumi.String("TCP"), Port: pulumi.Int(443)},
+						},
+					},
+				},
+			}
+		}
+
+		// The pods fetch Workload Identity tokens from the GKE Metadata Server
+		if traffic.MetadataServer {
+			policies["allow-metadata-server"] = &networkingv1.NetworkPolicySpecArgs{
+				PodSelector: &metav1.LabelSelectorArgs{},
+				PolicyTypes: pulumi.ToStringArray([]string{"Egress"}),
+				Egress: networkingv1.NetworkPolicyEgressRuleArray{
+					&networkingv1.NetworkPolicyEgressRuleArgs{
+						To: ipBlockPeers([]string{metadataServerCidr}),
+						Ports: networkingv1.NetworkPolicyPortArray{
+							&networkingv1.NetworkPolicyPortArgs{Protocol: pulumi.String("TCP"), Port: pulumi.Int(metadataServerPort)},
+						},
+					},
+					&networkingv1.NetworkPolicyEgressRuleArgs{
+						To: ipBlockPeers([]string{metadataEndpointCidr}),
+						Ports: networkingv1.NetworkPolicyPortArray{
+							&networkingv1.NetworkPolicyPortArgs{Protocol: pulumi.String("TCP"), Port: pulumi.Int(metadataEndpointPort)},
+						},
+					},
+				},
+			}
+		}
+
+		// Additional allowed destinations
+		if len(namespaceBaseline.EgressCidrs) > 0 {
+			policies["allow-egress-cidrs"] = &networkingv1.NetworkPolicySpecArgs{
+				PodSelector: &metav1.LabelSelectorArgs{},
+				PolicyTypes: pulumi.ToStringArray([]string{"Egress"}),
+				Egress: networkingv1.NetworkPolicyEgressRuleArray{
+					&networkingv1.NetworkPolicyEgressRuleArgs{
+						To: ipBlockPeers(namespaceBaseline.EgressCidrs),
+					},
+				},
+			}
+		}
+
+		policyNames := make([]string, 0, len(policies))
+		for name := range policies {
+			policyNames = append(policyNames, name)
+		}
+		sort.Strings(policyNames)
+		for _, name := range policyNames {
+			resourceName = fmt.Sprintf("%s-k8s-netpol-%s-%s", resourceNamePrefix, name, resourceNameSuffix)
+			k8sNetworkPolicy, err := networkingv1.NewNetworkPolicy(ctx, resourceName, &networkingv1.NetworkPolicyArgs{
+				Metadata: &metav1.ObjectMetaArgs{
+					Name:      pulumi.String(name),
+					Namespace: appNamespace,
+				},
+				Spec: policies[name],
+			}, opts...)
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, k8sNetworkPolicy)
+		}
+	}
+	return resources, nil
+}
+
+// Function - A Label Selector matching a namespace by name
+func namespaceSelector(namespace string) *metav1.LabelSelectorArgs {
+	return &metav1.LabelSelectorArgs{
+		MatchLabels: pulumi.StringMap{
+			"kubernetes.io/metadata.name": pulumi.String(namespace),
+		},
+	}
+}
+
+// Function - A NetworkPolicy Peer matching every pod of a namespace
+func namespacePeer(namespace string) *networkingv1.NetworkPolicyPeerArgs {
+	return &networkingv1.NetworkPolicyPeerArgs{
+		NamespaceSelector: namespaceSelector(namespace),
+	}
+}
+
+// Function - A Label Selector matching the Istio Ingress Gateway pods
+func ingressGatewaySelector() *metav1.LabelSelectorArgs {
+	return &metav1.LabelSelectorArgs{
+		MatchLabels: pulumi.StringMap{
+			"istio": pulumi.String("ingressgateway"),
+		},
+	}
+}
+
+// Function - NetworkPolicy Peers matching CIDR ranges
+func ipBlockPeers(cidrs []string) networkingv1.NetworkPolicyPeerArray {
+	peers := networkingv1.NetworkPolicyPeerArray{}
+	for _, cidr := range cidrs {
+		peers = append(peers, &networkingv1.NetworkPolicyPeerArgs{
+			IpBlock: &networkingv1.IPBlockArgs{
+				Cidr: pulumi.String(cidr),
+			},
+		})
+	}
+	return peers
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Application Canary; A second Deployment running 'tag' next to the stable Deployment. The Istio VirtualService
+// sends it 'weight' percent of the traffic, overridden per region (keyed by region). Setting a weight back to 0 rolls back.
+type appCanaryConfig struct {
+	Image    string         `json:"image"`
+	Tag      string         `json:"tag"`
+	Replicas int            `json:"replicas"`
+	Weight   int            `json:"weight"`
+	Regions  map[string]int `json:"regions"`
+}
+
+// Function - Validate & default an Application's Canary configuration
+func (c *appCanaryConfig) validate(app string) error {
+	if c.Tag == "" {
+		return fmt.Errorf("[CONFIGURATION] - Applications: '%s' canary must set a 'tag'", app)
+	}
+	if c.Replicas == 0 {
+		c.Replicas = 1
+	}
+	if c.Replicas < 1 {
+		return fmt.Errorf("[CONFIGURATION] - Applications: '%s' canary 'replicas' must be at least 1", app)
+	}
+	if c.Weight < 0 || c.Weight > 100 {
+		return fmt.Errorf("[CONFIGURATION] - Applications: '%s' canary 'weight' %d must be between 0 and 100", app, c.Weight)
+	}
+	fmt.Printf("[CONFIGURATION] - Application: '%s' - Canary tag '%s' receives %d%% of the traffic.\n", app, c.Tag, c.Weight)
+
+	regions := make([]string, 0, len(c.Regions))
+	for region := range c.Regions {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	for _, region := range regions {
+		weight := c.Regions[region]
+		if !isCloudRegion(region) {
+			return fmt.Errorf("[CONFIGURATION] - Applications: '%s' canary has a weight for unknown region '%s'", app, region)
+		}
+		if weight < 0 || weight > 100 {
+			return fmt.Errorf("[CONFIGURATION] - Applications: '%s' canary weight %d for region %s must be between 0 and 100", app, weight, region)
+		}
+		fmt.Printf("[CONFIGURATION] - Application: '%s' - Region %s sends %d%% of the traffic to the canary.\n", app, region, weight)
+	}
+	return nil
+}
+
+// Function - The percent of traffic sent to the canary in a region; The region override or the stack-wide weight
+func (c *appCanaryConfig) weightForRegion(region string) int {
+	if weight, ok := c.Regions[region]; ok {
+		return weight
+	}
+	return c.Weight
+}
+
+// Function - Helm values for the canary Deployment & VirtualService weights of the 'app-team' chart in a region
+func (c *appCanaryConfig) helmValues(region string) map[string]interface{} {
+	return map[string]interface{}{
+		"canary": map[string]interface{}{
+			"enabled":      true,
+			"weight":       c.weightForRegion(region),
+			"replicaCount": c.Replicas,
+			"image":        c.Image,
+			"tag":          c.Tag,
+		},
+	}
+}
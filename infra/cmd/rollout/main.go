@@ -0,0 +1,454 @@
+// Rollout - Apply the changes of a stack one Cloud Region at a time.
+//
+// The pending changes are previewed and grouped by Cloud Region; A resource belongs to the region whose
+// root resources (Subnet, Cluster, Node Pool, Kubernetes Provider & Fleet Memberships) its parent or provider
+// chain leads to. Global resources are applied first, then each region is applied with targeted updates (including
+// the region's & global resources that depend on the targets; Dependents in other regions are reported & left to
+// their own stage) and must be healthy (Deployments available & NEG endpoints healthy) before the next region is
+// started. A failing region halts the rollout.
+//
+// Usage (from the 'infra' directory): go run ./cmd/rollout -stack <stack>
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
+)
+
+// A Cloud Region as exported by the stack ('cloudRegions' output)
+type rolloutRegion struct {
+	Id         string   `json:"id"`
+	Region     string   `json:"region"`
+	Cluster    string   `json:"cluster"`
+	Namespaces []string `json:"namespaces"`
+	// URNs of the region's root resources
+	Roots []string `json:"roots"`
+}
+
+// A resource of the stack as seen by the preview
+type previewResource struct {
+	URN      string
+	Parent   string
+	Provider string
+	// Whether the preview would create, update, replace or delete it
+	Changed bool
+}
+
+// A set of resources applied together, followed by a health check
+type rolloutStage struct {
+	Name   string
+	Region *rolloutRegion
+	URNs   []string
+	// Unchanged resources of the region (or global) depending on the changed ones; Applied with them
+	Dependents []string
+	// Resources of other regions depending on the changed ones; Not applied by the stage
+	Excluded []string
+}
+
+// A resource of the stack's state with the resources (and provider) it depends on
+type stateResource struct {
+	URN                  string              `json:"urn"`
+	Provider             string              `json:"provider"`
+	Dependencies         []string            `json:"dependencies"`
+	PropertyDependencies map[string][]string `json:"propertyDependencies"`
+}
+
+func main() {
+	stackName := flag.String("stack", "", "The Pulumi stack to roll out (Required)")
+	workDir := flag.String("dir", ".", "The directory of the Pulumi project")
+	timeout := flag.Duration("timeout", 10*time.Minute, "How long to wait for a region to become healthy")
+	dryRun := flag.Bool("dry-run", false, "Only print the rollout plan")
+	flag.Parse()
+	if *stackName == "" {
+		fmt.Fprintln(os.Stderr, "[ ERROR ] - The '-stack' flag is required")
+		os.Exit(2)
+	}
+
+	if err := rollout(context.Background(), *stackName, *workDir, *timeout, *dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "[ ERROR ] - %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Function - Plan & apply the rollout stage by stage
+func rollout(ctx context.Context, stackName string, workDir string, timeout time.Duration, dryRun bool) error {
+	stack, err := auto.SelectStackLocalSource(ctx, stackName, workDir)
+	if err != nil {
+		return fmt.Errorf("select stack '%s': %w", stackName, err)
+	}
+	project, err := stack.GetConfig(ctx, "gcp:project")
+	if err != nil {
+		return fmt.Errorf("read 'gcp:project': %w", err)
+	}
+	outputs, err := stack.Outputs(ctx)
+	if err != nil {
+		return fmt.Errorf("read stack outputs: %w", err)
+	}
+	regions := []*rolloutRegion{}
+	if output, ok := outputs["cloudRegions"]; ok {
+		if err := decodeOutput(output.Value, &regions); err != nil {
+			return fmt.Errorf("read 'cloudRegions' output: %w", err)
+		}
+	}
+	backendService := ""
+	if output, ok := outputs["backendService"]; ok {
+		backendService, _ = output.Value.(string)
+	}
+
+	// Preview the stack & group the pending changes by Cloud Region
+	fmt.Printf("[ INFORMATION ] - Previewing stack '%s'...\n", stackName)
+	resources, err := previewResources(ctx, stack)
+	if err != nil {
+		return err
+	}
+	dependents, err := stateDependents(ctx, stack)
+	if err != nil {
+		return err
+	}
+	stages := planStages(resources, regions, dependents)
+	for _, stage := range stages {
+		fmt.Printf("[ INFORMATION ] - Stage: %s - %d resource change(s), %d dependent(s)\n", stage.Name, len(stage.URNs), len(stage.Dependents))
+		for _, urn := range stage.Excluded {
+			fmt.Printf("[ WARNING ] - Stage: %s - Dependent of another region left to that region's stage or the final reconcile: %s\n", stage.Name, urn)
+		}
+	}
+	if dryRun {
+		return nil
+	}
+
+	completed := []string{}
+	for i, stage := range stages {
+		err := applyStage(ctx, stack, stage, project.Value, backendService, timeout)
+		if err != nil {
+			pending := []string{}
+			for _, next := range stages[i+1:] {
+				pending = append(pending, next.Name)
+			}
+			fmt.Printf("[ ROLLOUT HALTED ] - Completed: [%s] - Failed: %s - Pending: [%s]\n", strings.Join(completed, ", "), stage.Name, strings.Join(pending, ", "))
+			return fmt.Errorf("stage %s: %w", stage.Name, err)
+		}
+		completed = append(completed, stage.Name)
+		fmt.Printf("[ INFORMATION ] - Stage: %s - Complete\n", stage.Name)
+	}
+
+	// Reconcile the stack outputs & anything not attributed to a stage
+	fmt.Printf("[ INFORMATION ] - Reconciling stack '%s'...\n", stackName)
+	if _, err := stack.Up(ctx, optup.ProgressStreams(os.Stdout)); err != nil {
+		return fmt.Errorf("reconcile: %w", err)
+	}
+	fmt.Printf("[ ROLLOUT COMPLETE ] - Stages: [%s]\n", strings.Join(completed, ", "))
+	return nil
+}
+
+// Function - Every resource of the preview with its parent & provider; Changed when it would be created, updated, replaced or deleted
+func previewResources(ctx context.Context, stack auto.Stack) ([]previewResource, error) {
+	engineEvents := make(chan events.EngineEvent)
+	collected := make(chan []previewResource)
+	go func() {
+		resources := []previewResource{}
+		seen := map[string]int{}
+		for event := range engineEvents {
+			if event.ResourcePreEvent == nil {
+				continue
+			}
+			metadata := event.ResourcePreEvent.Metadata
+			if metadata.Type == "pulumi:pulumi:Stack" {
+				continue
+			}
+			resource := previewResource{
+				URN:     metadata.URN,
+				Changed: metadata.Op != "same" && metadata.Op != "read",
+			}
+			state := metadata.New
+			if state == nil {
+				state = metadata.Old
+			}
+			if state != nil {
+				resource.Parent = state.Parent
+				resource.Provider = providerURN(state.Provider)
+			}
+			// A replacement emits more than one step for the same URN
+			if i, ok := seen[metadata.URN]; ok {
+				resources[i].Changed = resources[i].Changed || resource.Changed
+				continue
+			}
+			seen[metadata.URN] = len(resources)
+			resources = append(resources, resource)
+		}
+		collected <- resources
+	}()
+	_, err := stack.Preview(ctx, optpreview.EventStreams(engineEvents))
+	resources := <-collected
+	if err != nil {
+		return nil, fmt.Errorf("preview: %w", err)
+	}
+	return resources, nil
+}
+
+// Function - The resources depending on each resource of the stack's current state (keyed by URN)
+func stateDependents(ctx context.Context, stack auto.Stack) (map[string][]string, error) {
+	deployment, err := stack.Export(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("export stack: %w", err)
+	}
+	state := struct {
+		Resources []stateResource `json:"resources"`
+	}{}
+	if err := json.Unmarshal(deployment.Deployment, &state); err != nil {
+		return nil, fmt.Errorf("read stack state: %w", err)
+	}
+	dependents := map[string][]string{}
+	for _, resource := range state.Resources {
+		seen := map[string]bool{}
+		dependencies := append([]string{providerURN(resource.Provider)}, resource.Dependencies...)
+		for _, urns := range resource.PropertyDependencies {
+			dependencies = append(dependencies, urns...)
+		}
+		for _, dependency := range dependencies {
+			if dependency == "" || seen[dependency] {
+				continue
+			}
+			seen[dependency] = true
+			dependents[dependency] = append(dependents[dependency], resource.URN)
+		}
+	}
+	return dependents, nil
+}
+
+// Function - The URN of a provider reference ('<urn>::<id>')
+func providerURN(reference string) string {
+	if i := strings.LastIndex(reference, "::"); i >= 0 {
+		return reference[:i]
+	}
+	return reference
+}
+
+// Function - Group the changed resources into a global stage followed by one stage per Cloud Region (Regions without changes are skipped).
+// A resource belongs to the region whose root resources its parent or provider chain leads to; Any other resource is global.
+// Regional stages also apply the unchanged resources of their region (or global ones) that depend on their changes; 'dependents'
+// are keyed by the URN they depend on. Dependents of another region are excluded from the stage.
+func planStages(resources []previewResource, regions []*rolloutRegion, dependents map[string][]string) []rolloutStage {
+	global := rolloutStage{Name: "global"}
+	regional := make([]rolloutStage, len(regions))
+	roots := map[string]int{}
+	for i, region := range regions {
+		regional[i] = rolloutStage{Name: region.Region, Region: region}
+		for _, urn := range region.Roots {
+			roots[urn] = i
+		}
+	}
+	byURN := map[string]previewResource{}
+	for _, resource := range resources {
+		byURN[resource.URN] = resource
+	}
+
+	for _, resource := range resources {
+		if !resource.Changed {
+			continue
+		}
+		if i := regionOf(resource.URN, byURN, roots, map[string]bool{}); i >= 0 {
+			regional[i].URNs = append(regional[i].URNs, resource.URN)
+		} else {
+			global.URNs = append(global.URNs, resource.URN)
+		}
+	}
+
+	stages := []rolloutStage{}
+	if len(global.URNs) > 0 {
+		stages = append(stages, global)
+	}
+	for i, stage := range regional {
+		if len(stage.URNs) == 0 {
+			continue
+		}
+		staged := map[string]bool{}
+		for _, urn := range stage.URNs {
+			staged[urn] = true
+		}
+		for queue := append([]string{}, stage.URNs...); len(queue) > 0; queue = queue[1:] {
+			for _, dependent := range dependents[queue[0]] {
+				if staged[dependent] {
+					continue
+				}
+				staged[dependent] = true
+				region := regionOf(dependent, byURN, roots, map[string]bool{})
+				if region >= 0 && region != i {
+					stage.Excluded = append(stage.Excluded, dependent)
+					continue
+				}
+				if !byURN[dependent].Changed {
+					stage.Dependents = append(stage.Dependents, dependent)
+				}
+				queue = append(queue, dependent)
+			}
+		}
+		stages = append(stages, stage)
+	}
+	return stages
+}
+
+// Function - The index of the region whose root resources a resource's parent or provider chain leads to; -1 when none
+func regionOf(urn string, byURN map[string]previewResource, roots map[string]int, visited map[string]bool) int {
+	if i, ok := roots[urn]; ok {
+		return i
+	}
+	resource, ok := byURN[urn]
+	if !ok || visited[urn] {
+		return -1
+	}
+	visited[urn] = true
+	for _, next := range []string{resource.Parent, resource.Provider} {
+		if next == "" {
+			continue
+		}
+		if i := regionOf(next, byURN, roots, visited); i >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Function - Apply the resources of a stage; Regional stages must then become healthy.
+// Regional stages also update their dependents; Their global dependencies are applied by the global stage first.
+func applyStage(ctx context.Context, stack auto.Stack, stage rolloutStage, project string, backendService string, timeout time.Duration) error {
+	fmt.Printf("[ INFORMATION ] - Stage: %s - Applying %d resource change(s)...\n", stage.Name, len(stage.URNs))
+	targets := append(append([]string{}, stage.URNs...), stage.Dependents...)
+	if _, err := stack.Up(ctx, optup.Target(targets), optup.ProgressStreams(os.Stdout)); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+	if stage.Region == nil {
+		return nil
+	}
+
+	fmt.Printf("[ INFORMATION ] - Cloud Region: %s - Waiting for Deployments to become available...\n", stage.Region.Region)
+	if err := waitForDeployments(ctx, stage.Region, project, timeout); err != nil {
+		return err
+	}
+	if backendService == "" {
+		fmt.Printf("[ INFORMATION ] - Cloud Region: %s - No global Backend Service exported; Skipping the NEG endpoint health check.\n", stage.Region.Region)
+		return nil
+	}
+	fmt.Printf("[ INFORMATION ] - Cloud Region: %s - Waiting for NEG endpoints of '%s' to become healthy...\n", stage.Region.Region, backendService)
+	return waitForNEGs(ctx, stage.Region, project, backendService, timeout)
+}
+
+// Function - Wait until every Deployment in the region's namespaces is available; Namespaces without Deployments are skipped
+func waitForDeployments(ctx context.Context, region *rolloutRegion, project string, timeout time.Duration) error {
+	kubeconfigDir, err := os.MkdirTemp("", "rollout-kubeconfig")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(kubeconfigDir)
+	env := append(os.Environ(), "KUBECONFIG="+filepath.Join(kubeconfigDir, "config"))
+
+	_, err = run(ctx, env, "gcloud", "container", "clusters", "get-credentials", region.Cluster, "--region", region.Region, "--project", project)
+	if err != nil {
+		return fmt.Errorf("cluster credentials: %w", err)
+	}
+	for _, namespace := range region.Namespaces {
+		// 'kubectl wait --all' fails when there is nothing to wait for
+		deployments, err := run(ctx, env, "kubectl", "get", "deployment", "--namespace", namespace, "--output=name")
+		if err != nil {
+			return fmt.Errorf("deployments in namespace '%s': %w", namespace, err)
+		}
+		if strings.TrimSpace(string(deployments)) == "" {
+			fmt.Printf("[ INFORMATION ] - Cloud Region: %s - No Deployments in namespace '%s'; Skipping.\n", region.Region, namespace)
+			continue
+		}
+		_, err = run(ctx, env, "kubectl", "wait", "deployment", "--all", "--for=condition=Available", "--namespace", namespace, fmt.Sprintf("--timeout=%s", timeout))
+		if err != nil {
+			return fmt.Errorf("deployments in namespace '%s' not available: %w", namespace, err)
+		}
+	}
+	return nil
+}
+
+// The result of 'gcloud compute backend-services get-health'
+type backendHealth struct {
+	Backend string `json:"backend"`
+	Status  struct {
+		HealthStatus []struct {
+			HealthState string `json:"healthState"`
+		} `json:"healthStatus"`
+	} `json:"status"`
+}
+
+// Function - Wait until every endpoint of the Backend Service's NEGs in the region's zones is healthy
+func waitForNEGs(ctx context.Context, region *rolloutRegion, project string, backendService string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		healthy, total, err := negHealth(ctx, region, project, backendService)
+		if err == nil && total > 0 && healthy == total {
+			fmt.Printf("[ INFORMATION ] - Cloud Region: %s - %d/%d NEG endpoint(s) healthy.\n", region.Region, healthy, total)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("NEG endpoints not healthy: %w", err)
+			}
+			return fmt.Errorf("NEG endpoints not healthy: %d/%d healthy after %s", healthy, total, timeout)
+		}
+		fmt.Printf("[ INFORMATION ] - Cloud Region: %s - %d/%d NEG endpoint(s) healthy; Waiting...\n", region.Region, healthy, total)
+		time.Sleep(15 * time.Second)
+	}
+}
+
+// Function - Count the healthy & total endpoints of the Backend Service's NEGs in the region's zones
+func negHealth(ctx context.Context, region *rolloutRegion, project string, backendService string) (int, int, error) {
+	output, err := run(ctx, nil, "gcloud", "compute", "backend-services", "get-health", backendService, "--global", "--project", project, "--format=json")
+	if err != nil {
+		return 0, 0, err
+	}
+	backends := []backendHealth{}
+	if err := json.Unmarshal(output, &backends); err != nil {
+		return 0, 0, err
+	}
+	healthy, total := 0, 0
+	for _, backend := range backends {
+		if !strings.Contains(backend.Backend, "/zones/"+region.Region+"-") {
+			continue
+		}
+		for _, endpoint := range backend.Status.HealthStatus {
+			total++
+			if endpoint.HealthState == "HEALTHY" {
+				healthy++
+			}
+		}
+	}
+	return healthy, total, nil
+}
+
+// Function - Run a command; Its output is returned & included in the error on failure
+func run(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = env
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return output, fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return output, fmt.Errorf("%s: %w", name, err)
+	}
+	return output, nil
+}
+
+// Function - Decode a stack output into a typed value
+func decodeOutput(value interface{}, target interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, target)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanStages(t *testing.T) {
+	urn := func(kind string, name string) string {
+		return "urn:pulumi:dev::gke-at-scale::" + kind + "::" + name
+	}
+	regions := []*rolloutRegion{
+		{Id: "ew1", Region: "europe-west1", Roots: []string{urn("gcp:container/cluster:Cluster", "gas-gke-europe-west1"), urn("pulumi:providers:kubernetes", "gas-gke-europe-west1-kubeconfig"), urn("gcp:gkehub/membership:Membership", "gas-fleet-membership-europe-west1")}},
+		{Id: "ew12", Region: "europe-west12", Roots: []string{urn("gcp:container/cluster:Cluster", "gas-gke-europe-west12"), urn("pulumi:providers:kubernetes", "gas-gke-europe-west12-kubeconfig")}},
+	}
+	resources := []previewResource{
+		// Global
+		{URN: urn("gcp:compute/network:Network", "gas-vpc"), Changed: true},
+		// Roots
+		{URN: regions[0].Roots[0], Changed: true},
+		{URN: regions[1].Roots[0]},
+		{URN: regions[0].Roots[1]},
+		{URN: regions[1].Roots[1]},
+		// Parented to a Cluster
+		{URN: urn("gcp:container/cluster:Cluster$kubernetes:helm.sh/v3:Release", "gas-app-europe-west12"), Parent: regions[1].Roots[0], Changed: true},
+		// Provisioned through a Kubernetes Provider; The name mentions the other region
+		{URN: urn("kubernetes:core/v1:Namespace", "gas-ns-europe-west12-copy"), Provider: regions[0].Roots[1], Changed: true},
+		// Parented to an unchanged resource provisioned through a Kubernetes Provider
+		{URN: urn("kubernetes:helm.sh/v3:Chart", "gas-chart"), Provider: regions[1].Roots[1]},
+		{URN: urn("kubernetes:helm.sh/v3:Chart$kubernetes:apps/v1:Deployment", "app"), Parent: urn("kubernetes:helm.sh/v3:Chart", "gas-chart"), Changed: true},
+		// Unchanged resources are not staged
+		{URN: urn("kubernetes:core/v1:Service", "gas-svc"), Provider: regions[0].Roots[1]},
+		// A global resource named after a region
+		{URN: urn("gcp:compute/globalAddress:GlobalAddress", "gas-ip-europe-west1"), Changed: true},
+		// A Fleet Membership is a root of its region
+		{URN: regions[0].Roots[2], Changed: true},
+	}
+
+	stages := planStages(resources, regions, nil)
+	got := map[string][]string{}
+	names := []string{}
+	for _, stage := range stages {
+		names = append(names, stage.Name)
+		got[stage.Name] = stage.URNs
+	}
+	if want := []string{"global", "europe-west1", "europe-west12"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("stages = %v, want %v", names, want)
+	}
+	want := map[string][]string{
+		"global":        {resources[0].URN, resources[10].URN},
+		"europe-west1":  {resources[1].URN, resources[6].URN, resources[11].URN},
+		"europe-west12": {resources[5].URN, resources[8].URN},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stages = %v, want %v", got, want)
+	}
+}
+
+func TestPlanStagesDependents(t *testing.T) {
+	regions := []*rolloutRegion{
+		{Region: "us-central1", Roots: []string{"urn:cluster-us-central1", "urn:provider-us-central1"}},
+		{Region: "us-east1", Roots: []string{"urn:cluster-us-east1", "urn:provider-us-east1"}},
+	}
+	resources := []previewResource{
+		{URN: "urn:cluster-us-central1"},
+		{URN: "urn:cluster-us-east1"},
+		{URN: "urn:provider-us-central1"},
+		{URN: "urn:provider-us-east1"},
+		{URN: "urn:token-us-central1", Provider: "urn:provider-us-central1", Changed: true},
+		{URN: "urn:release-us-central1", Parent: "urn:cluster-us-central1"},
+		{URN: "urn:remote-secret-us-central1", Provider: "urn:provider-us-central1"},
+		{URN: "urn:remote-secret-us-east1", Provider: "urn:provider-us-east1"},
+		{URN: "urn:workload-identity"},
+	}
+	dependents := map[string][]string{
+		"urn:token-us-central1":   {"urn:release-us-central1", "urn:remote-secret-us-east1"},
+		"urn:release-us-central1": {"urn:workload-identity"},
+		// Dependents of an excluded resource are not followed
+		"urn:remote-secret-us-east1": {"urn:remote-secret-us-central1"},
+	}
+	stages := planStages(resources, regions, dependents)
+	if len(stages) != 1 || stages[0].Name != "us-central1" {
+		t.Fatalf("stages = %+v, want only us-central1", stages)
+	}
+	if want := []string{"urn:release-us-central1", "urn:workload-identity"}; !reflect.DeepEqual(stages[0].Dependents, want) {
+		t.Errorf("dependents = %v, want %v", stages[0].Dependents, want)
+	}
+	if want := []string{"urn:remote-secret-us-east1"}; !reflect.DeepEqual(stages[0].Excluded, want) {
+		t.Errorf("excluded = %v, want %v", stages[0].Excluded, want)
+	}
+}
+
+func TestPlanStagesSkipsRegionsWithoutChanges(t *testing.T) {
+	regions := []*rolloutRegion{
+		{Region: "us-central1", Roots: []string{"urn:cluster-us-central1"}},
+		{Region: "us-east1", Roots: []string{"urn:cluster-us-east1"}},
+	}
+	resources := []previewResource{
+		{URN: "urn:cluster-us-central1"},
+		{URN: "urn:release", Parent: "urn:cluster-us-central1"},
+		{URN: "urn:pool", Parent: "urn:cluster-us-east1", Changed: true},
+	}
+	stages := planStages(resources, regions, nil)
+	if len(stages) != 1 || stages[0].Name != "us-east1" || stages[0].Region != regions[1] {
+		t.Fatalf("stages = %+v, want only us-east1", stages)
+	}
+}
+
+func TestPlanStagesParentCycle(t *testing.T) {
+	resources := []previewResource{
+		{URN: "urn:a", Parent: "urn:b", Changed: true},
+		{URN: "urn:b", Parent: "urn:a"},
+	}
+	stages := planStages(resources, []*rolloutRegion{{Region: "us-east1", Roots: []string{"urn:c"}}}, nil)
+	if len(stages) != 1 || stages[0].Name != "global" {
+		t.Fatalf("stages = %+v, want only global", stages)
+	}
+}
+
+func TestProviderURN(t *testing.T) {
+	for reference, want := range map[string]string{
+		"urn:pulumi:dev::gke-at-scale::pulumi:providers:kubernetes::gas-gke-us-east1-kubeconfig::04da6b54-80e4-46f7-96ec-b56ff0331ba9": "urn:pulumi:dev::gke-at-scale::pulumi:providers:kubernetes::gas-gke-us-east1-kubeconfig",
+		"": "",
+	} {
+		if got := providerURN(reference); got != want {
+			t.Errorf("providerURN(%q) = %q, want %q", reference, got, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes"
+	"github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/apiextensions"
+	metav1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/meta/v1"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// GKE Datapaths
+const (
+	// The default kube-proxy & iptables datapath; NetworkPolicies are not enforced
+	datapathLegacy = "legacy"
+	// GKE Dataplane V2 (eBPF); NetworkPolicies are enforced
+	datapathAdvanced = "advanced"
+)
+
+// GKE Datapath Configuration
+type datapathConfig struct {
+	Datapath string
+	// Log denied connections; Allowed connections are logged for namespaces annotated 'policy.network.gke.io/enable-logging: "true"'
+	NetworkPolicyLogging bool
+}
+
+// Function - Read & Validate the GKE Datapath configuration. Changing the datapath of an existing cluster recreates it.
+func loadDatapathConfig(cfg *config.Config) (*datapathConfig, error) {
+	datapath := &datapathConfig{
+		Datapath:             cfg.Get("datapath"),
+		NetworkPolicyLogging: cfg.GetBool("networkPolicyLogging"),
+	}
+	switch datapath.Datapath {
+	case "", datapathLegacy:
+		datapath.Datapath = datapathLegacy
+		if datapath.NetworkPolicyLogging {
+			return nil, fmt.Errorf("[CONFIGURATION] - Network Policy Logging: requires Datapath '%s'", datapathAdvanced)
+		}
+	case datapathAdvanced:
+	default:
+		return nil, fmt.Errorf("[CONFIGURATION] - Datapath: '%s' must be '%s' or '%s'", datapath.Datapath, datapathLegacy, datapathAdvanced)
+	}
+	fmt.Printf("[CONFIGURATION] - Datapath: '%s'; Network Policy Logging: %t.\n", datapath.Datapath, datapath.NetworkPolicyLogging)
+	return datapath, nil
+}
+
+// Function - The GKE Datapath Provider of the clusters; Unset for the legacy datapath so existing clusters are left unchanged
+func (d *datapathConfig) provider() pulumi.StringPtrInput {
+	if d.Datapath == datapathAdvanced {
+		return pulumi.String("ADVANCED_DATAPATH")
+	}
+	return nil
+}
+
+// Function - Whether the clusters enforce NetworkPolicies
+func (d *datapathConfig) enforcesNetworkPolicies() bool {
+	return d.Datapath == datapathAdvanced
+}
+
+// Function - Configure Dataplane V2 Network Policy Logging in a cluster; Patches the cluster's 'default' NetworkLogging object
+func createNetworkPolicyLogging(ctx *pulumi.Context, resourceNamePrefix string, region string, opts ...pulumi.ResourceOption) error {
+	resourceName := fmt.Sprintf("%s-k8s-network-logging-%s", resourceNamePrefix, region)
+	_, err := apiextensions.NewCustomResourcePatch(ctx, resourceName, &apiextensions.CustomResourcePatchArgs{
+		ApiVersion: pulumi.String("networking.gke.io/v1alpha1"),
+		Kind:       pulumi.String("NetworkLogging"),
+		Metadata: &metav1.ObjectMetaArgs{
+			Name: pulumi.String("default"),
+			Annotations: pulumi.StringMap{
+				"pulumi.com/patchForce": pulumi.String("true"),
+			},
+		},
+		OtherFields: kubernetes.UntypedArgs{
+			"spec": pulumi.Map{
+				"cluster": pulumi.Map{
+					"allow": pulumi.Map{
+						"log":      pulumi.Bool(false),
+						"delegate": pulumi.Bool(true),
+					},
+					"deny": pulumi.Map{
+						"log":      pulumi.Bool(true),
+						"delegate": pulumi.Bool(false),
+					},
+				},
+			},
+		},
+	}, opts...)
+	return err
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/container"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/gkehub"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// Istio Control Planes; Where the Service Mesh control plane for each regional cluster runs.
+const (
+	// Istio Base & Istiod installed into every cluster with Helm (Revisioned)
+	istioControlPlaneInCluster = "in-cluster"
+	// Google managed Cloud Service Mesh; The control plane is provisioned & upgraded by the Fleet
+	istioControlPlaneManaged = "managed"
+)
+
+// Google API's required to register Clusters with the Fleet
+var FleetServices = []string{
+	"gkehub.googleapis.com",
+}
+
+// Google API's required by the managed Cloud Service Mesh
+var ManagedMeshServices = []string{
+	"mesh.googleapis.com",
+}
+
+// Fleet Configuration
+type fleetConfig struct {
+	// Register every regional cluster as a Fleet Membership
+	Enabled bool
+	// Use the managed Cloud Service Mesh instead of the in-cluster Istio Helm installs
+	ManagedMesh bool
+}
+
+// Function - Read & Validate the Fleet configuration
+func loadFleetConfig(cfg *config.Config) (*fleetConfig, error) {
+	fleet := &fleetConfig{
+		Enabled: cfg.GetBool("enableFleet"),
+	}
+	switch controlPlane := cfg.Get("istioControlPlane"); controlPlane {
+	case "", istioControlPlaneInCluster:
+	case istioControlPlaneManaged:
+		fleet.ManagedMesh = true
+	default:
+		return nil, fmt.Errorf("[CONFIGURATION] - Istio Control Plane: '%s' must be '%s' or '%s'", controlPlane, istioControlPlaneInCluster, istioControlPlaneManaged)
+	}
+	if fleet.ManagedMesh && !fleet.Enabled {
+		fmt.Printf("[CONFIGURATION] - Fleet: The managed Cloud Service Mesh requires Fleet Membership; Every cluster will be registered to the Fleet.\n")
+		fleet.Enabled = true
+	}
+	if fleet.Enabled {
+		fmt.Printf("[CONFIGURATION] - Fleet: Enabled; Every regional cluster will be registered to the Fleet.\n")
+	}
+	if fleet.ManagedMesh {
+		fmt.Printf("[CONFIGURATION] - Istio Control Plane: %s; Cloud Service Mesh will be managed automatically by the Fleet.\n", istioControlPlaneManaged)
+	}
+	return fleet, nil
+}
+
+// Function - The Google API's required by the enabled Fleet features
+func (c *fleetConfig) services() []string {
+	services := []string{}
+	if c.Enabled {
+		services = append(services, FleetServices...)
+	}
+	if c.ManagedMesh {
+		services = append(services, ManagedMeshServices...)
+	}
+	return services
+}
+
+// Function - Register a regional GKE Cluster with the Fleet
+func createFleetMembership(ctx *pulumi.Context, resourceNamePrefix string, region string, gcpProjectId string, cluster *container.Cluster, clusterName pulumi.StringInput, opts ...pulumi.ResourceOption) (*gkehub.Membership, error) {
+	resourceName := fmt.Sprintf("%s-fleet-membership-%s", resourceNamePrefix, region)
+	return gkehub.NewMembership(ctx, resourceName, &gkehub.MembershipArgs{
+		Project:      pulumi.String(gcpProjectId),
+		MembershipId: clusterName,
+		Description:  pulumi.String(fmt.Sprintf("GKE at Scale - Fleet Membership - %s", region)),
+		Endpoint: &gkehub.MembershipEndpointArgs{
+			GkeCluster: &gkehub.MembershipEndpointGkeClusterArgs{
+				ResourceLink: pulumi.Sprintf("//container.googleapis.com/%s", cluster.ID()),
+			},
+		},
+		Authority: &gkehub.MembershipAuthorityArgs{
+			Issuer: pulumi.Sprintf("https://container.googleapis.com/v1/%s", cluster.ID()),
+		},
+	}, opts...)
+}
+
+// Function - Enable the Cloud Service Mesh feature on the Fleet
+func createFleetMeshFeature(ctx *pulumi.Context, resourceNamePrefix string, gcpProjectId string, opts ...pulumi.ResourceOption) (*gkehub.Feature, error) {
+	resourceName := fmt.Sprintf("%s-fleet-feature-mesh", resourceNamePrefix)
+	return gkehub.NewFeature(ctx, resourceName, &gkehub.FeatureArgs{
+		Project:  pulumi.String(gcpProjectId),
+		Name:     pulumi.String("servicemesh"),
+		Location: pulumi.String("global"),
+	}, opts...)
+}
+
+// Function - Let the Fleet provision & upgrade the Cloud Service Mesh control plane for a Membership
+func createFleetMeshMembership(ctx *pulumi.Context, resourceNamePrefix string, region string, gcpProjectId string, feature *gkehub.Feature, membership *gkehub.Membership, opts ...pulumi.ResourceOption) (*gkehub.FeatureMembership, error) {
+	resourceName := fmt.Sprintf("%s-fleet-mesh-membership-%s", resourceNamePrefix, region)
+	return gkehub.NewFeatureMembership(ctx, resourceName, &gkehub.FeatureMembershipArgs{
+		Project:    pulumi.String(gcpProjectId),
+		Location:   pulumi.String("global"),
+		Feature:    feature.Name,
+		Membership: membership.MembershipId,
+		Mesh: &gkehub.FeatureMembershipMeshArgs{
+			Management: pulumi.String("MANAGEMENT_AUTOMATIC"),
+		},
+	}, opts...)
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes"
+	"github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/apiextensions"
+	metav1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/meta/v1"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// Namespace the Istio Ingress Gateway is installed into when 'ingressGatewayNamespace' is not configured;
+// Applications in other namespaces are routed through it
+const ingressGatewayDefaultNamespace = "app-team"
+
+// Istio Gateway shared by every Application; VirtualServices bind to '<ingress gateway namespace>/ingress-gateway'
+const ingressGatewayName = "ingress-gateway"
+
+// Kubernetes TLS Secret the Istio Gateway terminates TLS from the Global Load Balancer with
+const ingressGatewayTLSSecretName = "istio-gateway-tls"
+
+// Status port & readiness path of the Istio Ingress Gateway; Probed by the Global Load Balancer Health Check
+const (
+	ingressGatewayStatusPort    = 15021
+	ingressGatewayReadinessPath = "/healthz/ready"
+)
+
+// Default Istio Ingress Gateway Scaling
+var gatewayScalingDefaults = scalingConfig{
+	MinReplicas:          2,
+	MaxReplicas:          5,
+	TargetCPUUtilization: 80,
+	Requests: map[string]string{
+		"cpu":    "100m",
+		"memory": "128Mi",
+	},
+	Limits: map[string]string{
+		"cpu":    "2000m",
+		"memory": "1024Mi",
+	},
+	PDBMinAvailable: pulumi.IntRef(1),
+	ZoneSpread:      pulumi.BoolRef(true),
+}
+
+// Function - Read & Validate the Istio Ingress Gateway configuration
+func loadIngressGatewayConfig(cfg *config.Config) (*regionalScalingConfig, error) {
+	return loadRegionalScalingConfig(cfg, "ingressGateway", "Ingress Gateway", gatewayScalingDefaults)
+}
+
+// Function - Helm values for the Istio 'gateway' chart
+func ingressGatewayHelmValues(g scalingConfig) pulumi.Map {
+	values := pulumi.Map{
+		"replicaCount": pulumi.Int(g.MinReplicas),
+		"autoscaling": pulumi.Map{
+			"enabled":                        pulumi.Bool(true),
+			"minReplicas":                    pulumi.Int(g.MinReplicas),
+			"maxReplicas":                    pulumi.Int(g.MaxReplicas),
+			"targetCPUUtilizationPercentage": pulumi.Int(g.TargetCPUUtilization),
+		},
+		"resources": pulumi.Map{
+			"requests": pulumi.ToStringMap(g.Requests),
+			"limits":   pulumi.ToStringMap(g.Limits),
+		},
+		// Meets the 'restricted' Pod Security level; The gateway binds ports 80 & 443 as a non-root user
+		"securityContext": pulumi.Map{
+			"sysctls": pulumi.Array{
+				pulumi.Map{
+					"name":  pulumi.String("net.ipv4.ip_unprivileged_port_start"),
+					"value": pulumi.String("0"),
+				},
+			},
+			"seccompProfile": pulumi.Map{
+				"type": pulumi.String("RuntimeDefault"),
+			},
+		},
+	}
+	// The chart only renders a PodDisruptionBudget when 'podDisruptionBudget' is set
+	if *g.PDBMinAvailable > 0 {
+		values["podDisruptionBudget"] = pulumi.Map{
+			"minAvailable": pulumi.Int(*g.PDBMinAvailable),
+		}
+	}
+	if *g.ZoneSpread {
+		values["topologySpreadConstraints"] = pulumi.Array{
+			pulumi.Map{
+				"maxSkew":           pulumi.Int(1),
+				"topologyKey":       pulumi.String("topology.kubernetes.io/zone"),
+				"whenUnsatisfiable": pulumi.String("ScheduleAnyway"),
+				"labelSelector": pulumi.Map{
+					"matchLabels": pulumi.StringMap{
+						"istio": pulumi.String("ingressgateway"),
+					},
+				},
+			},
+		}
+	}
+	return values
+}
+
+// Function - Create the Istio Gateway shared by every Application in a cluster; HTTP on port 80, and HTTPS on port 443 terminating
+// TLS from the Global Load Balancer when 'tls' is enabled. Applications in any namespace bind to it by host.
+func createIngressGateway(ctx *pulumi.Context, resourceNamePrefix string, region string, namespace pulumi.StringInput, tls bool, opts ...pulumi.ResourceOption) error {
+	servers := pulumi.Array{
+		pulumi.Map{
+			"port": pulumi.Map{
+				"number":   pulumi.Int(80),
+				"name":     pulumi.String("http"),
+				"protocol": pulumi.String("HTTP"),
+			},
+			"hosts": pulumi.StringArray{pulumi.String("*")},
+		},
+	}
+	if tls {
+		servers = append(servers, pulumi.Map{
+			"port": pulumi.Map{
+				"number":   pulumi.Int(443),
+				"name":     pulumi.String("https"),
+				"protocol": pulumi.String("HTTPS"),
+			},
+			"tls": pulumi.Map{
+				"mode":           pulumi.String("SIMPLE"),
+				"credentialName": pulumi.String(ingressGatewayTLSSecretName),
+			},
+			"hosts": pulumi.StringArray{pulumi.String("*")},
+		})
+	}
+
+	resourceName := fmt.Sprintf("%s-istio-gateway-%s", resourceNamePrefix, region)
+	_, err := apiextensions.NewCustomResource(ctx, resourceName, &apiextensions.CustomResourceArgs{
+		ApiVersion: pulumi.String("networking.istio.io/v1alpha3"),
+		Kind:       pulumi.String("Gateway"),
+		Metadata: &metav1.ObjectMetaArgs{
+			Name:      pulumi.String(ingressGatewayName),
+			Namespace: namespace,
+		},
+		OtherFields: kubernetes.UntypedArgs{
+			"spec": pulumi.Map{
+				"selector": pulumi.StringMap{
+					"istio": pulumi.String("ingressgateway"),
+				},
+				"servers": servers,
+			},
+		},
+	}, opts...)
+	return err
+}
+
+// The Applications routed by one Istio root VirtualService on the shared Gateway; Longest path prefix first
+type ingressRoute struct {
+	Host         string
+	Applications []*application
+}
+
+// Function - Group the Applications deployed to a region by VirtualService host. Istio merges the VirtualServices of a host
+// in no guaranteed order, so one root VirtualService per host matches the path prefixes longest first ('/shop/' before '/')
+// and delegates each to the Application's own VirtualService.
+func ingressRoutes(applications []*application, region string) []ingressRoute {
+	byHost := map[string][]*application{}
+	hosts := []string{}
+	for _, app := range applications {
+		if !app.deployedTo(region) {
+			continue
+		}
+		host := app.ingressHost()
+		if _, ok := byHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		byHost[host] = append(byHost[host], app)
+	}
+	sort.Strings(hosts)
+
+	routes := []ingressRoute{}
+	for _, host := range hosts {
+		apps := byHost[host]
+		sort.SliceStable(apps, func(i, j int) bool {
+			return len(apps[i].pathPrefix()) > len(apps[j].pathPrefix())
+		})
+		routes = append(routes, ingressRoute{Host: host, Applications: apps})
+	}
+	return routes
+}
+
+// Function - The name of the root VirtualService of a host, eg. 'ingress-shop.example.com' or 'ingress-any' for '*'
+func (r ingressRoute) name() string {
+	if r.Host == "*" {
+		return "ingress-any"
+	}
+	return fmt.Sprintf("ingress-%s", r.Host)
+}
+
+// Function - Create the root VirtualServices binding the Applications' hosts to the shared Istio Gateway; Each path prefix
+// is delegated to the '<application>-ingress' VirtualService rendered by the Application's chart in its namespace.
+func createIngressRoutes(ctx *pulumi.Context, resourceNamePrefix string, region string, namespace pulumi.StringInput, routes []ingressRoute, opts ...pulumi.ResourceOption) error {
+	for _, route := range routes {
+		http := pulumi.Array{}
+		for _, app := range route.Applications {
+			http = append(http, pulumi.Map{
+				"name": pulumi.String(app.Name),
+				"match": pulumi.Array{
+					pulumi.Map{
+						"uri": pulumi.Map{
+							"prefix": pulumi.String(app.pathPrefix()),
+						},
+					},
+				},
+				"delegate": pulumi.Map{
+					"name":      pulumi.String(fmt.Sprintf("%s-ingress", app.Name)),
+					"namespace": pulumi.String(app.Namespace),
+				},
+			})
+		}
+
+		resourceName := fmt.Sprintf("%s-istio-%s-%s", resourceNamePrefix, route.name(), region)
+		_, err := apiextensions.NewCustomResource(ctx, resourceName, &apiextensions.CustomResourceArgs{
+			ApiVersion: pulumi.String("networking.istio.io/v1alpha3"),
+			Kind:       pulumi.String("VirtualService"),
+			Metadata: &metav1.ObjectMetaArgs{
+				Name:      pulumi.String(route.name()),
+				Namespace: namespace,
+			},
+			OtherFields: kubernetes.UntypedArgs{
+				"spec": pulumi.Map{
+					"hosts":    pulumi.StringArray{pulumi.String(route.Host)},
+					"gateways": pulumi.StringArray{pulumi.String(ingressGatewayName)},
+					"http":     http,
+				},
+			},
+		}, opts...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
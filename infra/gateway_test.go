@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIngressRoutes(t *testing.T) {
+	// The README catalog; Both applications are routed from the Domain
+	applications := []*application{
+		{Name: "up-and-running", Namespace: "app-team", Path: "/*"},
+		{Name: "shop", Namespace: "shop", Path: "/shop/*", Regions: []string{"europe-west6"}},
+		{Name: "docs", Namespace: "docs", Host: "docs.example.com", Path: "/*"},
+	}
+	tests := []struct {
+		region   string
+		expected map[string][]string
+	}{
+		{"europe-west6", map[string][]string{"*": {"shop", "up-and-running"}, "docs.example.com": {"docs"}}},
+		{"us-east1", map[string][]string{"*": {"up-and-running"}, "docs.example.com": {"docs"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.region, func(t *testing.T) {
+			routes := ingressRoutes(applications, test.region)
+			got := map[string][]string{}
+			hosts := []string{}
+			for _, route := range routes {
+				hosts = append(hosts, route.Host)
+				for _, app := range route.Applications {
+					got[route.Host] = append(got[route.Host], app.Name)
+				}
+			}
+			if want := []string{"*", "docs.example.com"}; !reflect.DeepEqual(hosts, want) {
+				t.Errorf("got hosts %v, expected %v", hosts, want)
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("got routes %v, expected %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestIngressRouteName(t *testing.T) {
+	for host, expected := range map[string]string{
+		"*":                "ingress-any",
+		"shop.example.com": "ingress-shop.example.com",
+	} {
+		if got := (ingressRoute{Host: host}).name(); got != expected {
+			t.Errorf("got '%s', expected '%s'", got, expected)
+		}
+	}
+}
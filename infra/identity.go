@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/projects"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/serviceaccount"
+	k8s "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/core/v1"
+	metav1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/meta/v1"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// Application Workload Identity; A Kubernetes Service Account (KSA) in every regional cluster
+// bound through Workload Identity to a Google Cloud Service Account (GSA) holding the given roles.
+type appIdentity struct {
+	Name              string   `json:"name"`
+	Namespace         string   `json:"namespace"`
+	KSAName           string   `json:"ksaName"`
+	Roles             []string `json:"roles"`
+	GCPServiceAccount *serviceaccount.Account
+}
+
+// Identity names are used in Service Account IDs & Pulumi resource names
+var appIdentityNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Function - Read & Validate the Application Workload Identities from the 'appIdentities' configuration
+func loadAppIdentities(cfg *config.Config) ([]*appIdentity, error) {
+	identities := []*appIdentity{}
+	if err := cfg.GetObject("appIdentities", &identities); err != nil {
+		return nil, fmt.Errorf("[CONFIGURATION] - App Identities: %w", err)
+	}
+	if err := validateAppIdentities(identities); err != nil {
+		return nil, err
+	}
+	for _, identity := range identities {
+		fmt.Printf("[CONFIGURATION] - App Identity: '%s' - KSA '%s/%s' will be bound to a Google Service Account with %d role(s).\n", identity.Name, identity.Namespace, identity.KSAName, len(identity.Roles))
+	}
+	return identities, nil
+}
+
+// Function - Default the KSA names & reject invalid or duplicate names and KSAs bound more than once
+func validateAppIdentities(identities []*appIdentity) error {
+	seenNames := map[string]bool{}
+	seenKSAs := map[string]string{}
+	for _, identity := range identities {
+		if identity.Name == "" || identity.Namespace == "" {
+			return fmt.Errorf("[CONFIGURATION] - App Identities: every identity must have a 'name' and a 'namespace'")
+		}
+		if !appIdentityNamePattern.MatchString(identity.Name) {
+			return fmt.Errorf("[CONFIGURATION] - App Identities: name '%s' must start with a lowercase letter and contain only lowercase letters, digits & hyphens", identity.Name)
+		}
+		if seenNames[identity.Name] {
+			return fmt.Errorf("[CONFIGURATION] - App Identities: identity '%s' is declared more than once", identity.Name)
+		}
+		seenNames[identity.Name] = true
+		if identity.KSAName == "" {
+			identity.KSAName = identity.Name
+		}
+		ksa := fmt.Sprintf("%s/%s", identity.Namespace, identity.KSAName)
+		if other, ok := seenKSAs[ksa]; ok {
+			return fmt.Errorf("[CONFIGURATION] - App Identities: '%s' and '%s' both bind the KSA '%s'", other, identity.Name, ksa)
+		}
+		seenKSAs[ksa] = identity.Name
+	}
+	return nil
+}
+
+// Function - Create the Google Cloud Service Account & Project IAM grants for each Application Workload Identity
+func createAppIdentityServiceAccounts(ctx *pulumi.Context, names *stackNamer, gcpProjectId string, resourceNamePrefix string, identities []*appIdentity, opts ...pulumi.ResourceOption) error {
+	for _, identity := range identities {
+		resourceName := fmt.Sprintf("%s-service-account-app-%s", resourceNamePrefix, identity.Name)
+		gcpServiceAccount, err := serviceaccount.NewAccount(ctx, resourceName, &serviceaccount.AccountArgs{
+			Project:     pulumi.String(gcpProjectId),
+			AccountId:   names.ServiceAccountId(fmt.Sprintf("app-%s", identity.Name)),
+			DisplayName: pulumi.String(fmt.Sprintf("GKE at Scale - App Identity - %s", identity.Name)),
+		}, opts...)
+		if err != nil {
+			return err
+		}
+		identity.GCPServiceAccount = gcpServiceAccount
+
+		for _, role := range identity.Roles {
+			resourceName = fmt.Sprintf("%s-iam-member-app-%s-%s", resourceNamePrefix, identity.Name, strings.TrimPrefix(role, "roles/"))
+			_, err = projects.NewIAMMember(ctx, resourceName, &projects.IAMMemberArgs{
+				Project: pulumi.String(gcpProjectId),
+				Role:    pulumi.String(role),
+				Member:  pulumi.Sprintf("serviceAccount:%s", gcpServiceAccount.Email),
+			}, opts...)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Function - Create the annotated Kubernetes Service Account for each Application Workload Identity in a regional cluster.
+// Namespaces not already in 'namespaces' are created (with the labels for the namespace) and added to it.
+func createAppIdentityKubernetesServiceAccounts(ctx *pulumi.Context, resourceNamePrefix string, region string, identities []*appIdentity, namespaces map[string]*k8s.Namespace, namespaceLabels func(namespace string) pulumi.StringMap, opts ...pulumi.ResourceOption) error {
+	for _, identity := range identities {
+		k8sNamespace, err := getOrCreateNamespace(ctx, resourceNamePrefix, region, identity.Namespace, namespaces, namespaceLabels(identity.Namespace), opts...)
+		if err != nil {
+			return err
+		}
+
+		resourceName := fmt.Sprintf("%s-k8s-sa-app-%s-%s", resourceNamePrefix, identity.Name, region)
+		_, err = k8s.NewServiceAccount(ctx, resourceName, &k8s.ServiceAccountArgs{
+			Metadata: &metav1.ObjectMetaArgs{
+				Name:      pulumi.String(identity.KSAName),
+				Namespace: k8sNamespace.Metadata.Name(),
+				Annotations: pulumi.StringMap{
+					"iam.gke.io/gcp-service-account": identity.GCPServiceAccount.Email,
+				},
+			},
+		}, opts...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Function - Allow each Application Kubernetes Service Account to impersonate its Google Cloud Service Account
+func bindAppIdentityWorkloadIdentity(ctx *pulumi.Context, gcpProjectId string, resourceNamePrefix string, identities []*appIdentity, opts ...pulumi.ResourceOption) ([]pulumi.Resource, error) {
+	members := []pulumi.Resource{}
+	for _, identity := range identities {
+		resourceName := fmt.Sprintf("%s-iam-member-app-%s-workload-identity", resourceNamePrefix, identity.Name)
+		member, err := serviceaccount.NewIAMMember(ctx, resourceName, &serviceaccount.IAMMemberArgs{
+			ServiceAccountId: identity.GCPServiceAccount.Name,
+			Role:             pulumi.String("roles/iam.workloadIdentityUser"),
+			Member:           pulumi.String(fmt.Sprintf("serviceAccount:%s.svc.id.goog[%s/%s]", gcpProjectId, identity.Namespace, identity.KSAName)),
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
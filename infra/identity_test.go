@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateAppIdentities(t *testing.T) {
+	tests := []struct {
+		name       string
+		identities []*appIdentity
+		expected   string
+	}{
+		{"valid", []*appIdentity{{Name: "payments", Namespace: "shop"}, {Name: "orders", Namespace: "shop", KSAName: "orders-ksa"}}, ""},
+		{"missing namespace", []*appIdentity{{Name: "payments"}}, "must have a 'name' and a 'namespace'"},
+		{"uppercase name", []*appIdentity{{Name: "Payments", Namespace: "shop"}}, "name 'Payments' must start with a lowercase letter"},
+		{"name starts with a digit", []*appIdentity{{Name: "1payments", Namespace: "shop"}}, "name '1payments' must start with a lowercase letter"},
+		{"invalid characters", []*appIdentity{{Name: "payments_api", Namespace: "shop"}}, "name 'payments_api' must start with a lowercase letter"},
+		{"duplicate name", []*appIdentity{{Name: "payments", Namespace: "shop"}, {Name: "payments", Namespace: "billing"}}, "identity 'payments' is declared more than once"},
+		{"duplicate ksa", []*appIdentity{{Name: "payments", Namespace: "shop", KSAName: "api"}, {Name: "orders", Namespace: "shop", KSAName: "api"}}, "'payments' and 'orders' both bind the KSA 'shop/api'"},
+		{"duplicate defaulted ksa", []*appIdentity{{Name: "payments", Namespace: "shop"}, {Name: "orders", Namespace: "shop", KSAName: "payments"}}, "both bind the KSA 'shop/payments'"},
+		{"same ksa in another namespace", []*appIdentity{{Name: "payments", Namespace: "shop", KSAName: "api"}, {Name: "orders", Namespace: "billing", KSAName: "api"}}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateAppIdentities(test.identities)
+			if test.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("got error '%v', expected it to contain '%s'", err, test.expected)
+			}
+		})
+	}
+}
+
+func TestValidateAppIdentitiesDefaultsKSAName(t *testing.T) {
+	identities := []*appIdentity{{Name: "payments", Namespace: "shop"}}
+	if err := validateAppIdentities(identities); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := identities[0].KSAName; got != "payments" {
+		t.Errorf("got '%s', expected '%s'", got, "payments")
+	}
+}
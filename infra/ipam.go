@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"net"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// Secondary IP Range Names of each regional Subnet
+const (
+	podsSecondaryRangeName     = "pods"
+	servicesSecondaryRangeName = "services"
+)
+
+// GKE Node Pool Autoscaling; Node counts are per zone of the regional cluster
+const (
+	nodePoolMinNodeCount = 1
+	nodePoolMaxNodeCount = 5
+	gkeRegionZones       = 3
+)
+
+// Cluster IP Capacity; Each Cloud Region's Subnet gets a pod & a service secondary range carved (by the
+// region's position in CloudRegions) from the 'podsCidr' & 'servicesCidr' supernets and sized for the cluster.
+type ipCapacityConfig struct {
+	MaxPodsPerNode int    `json:"maxPodsPerNode"`
+	MaxNodes       int    `json:"maxNodes"`
+	MaxServices    int    `json:"maxServices"`
+	PodsCidr       string `json:"podsCidr"`
+	ServicesCidr   string `json:"servicesCidr"`
+	podsSize       uint32
+	servicesSize   uint32
+}
+
+// Default Cluster IP Capacity; A /24 per node, a /20 pod range (16 nodes) & a /22 service range per region
+var ipCapacityDefaults = ipCapacityConfig{
+	MaxPodsPerNode: 110,
+	MaxNodes:       16,
+	MaxServices:    1024,
+	PodsCidr:       "10.64.0.0/12",
+	ServicesCidr:   "10.96.0.0/16",
+}
+
+// Function - Read & Validate the Cluster IP Capacity from the 'ipCapacity' configuration
+func loadIpCapacityConfig(cfg *config.Config) (*ipCapacityConfig, error) {
+	capacity := &ipCapacityConfig{}
+	if err := cfg.GetObject("ipCapacity", capacity); err != nil {
+		return nil, fmt.Errorf("[CONFIGURATION] - IP Capacity: %w", err)
+	}
+	if capacity.MaxPodsPerNode == 0 {
+		capacity.MaxPodsPerNode = ipCapacityDefaults.MaxPodsPerNode
+	}
+	if capacity.MaxNodes == 0 {
+		capacity.MaxNodes = ipCapacityDefaults.MaxNodes
+	}
+	if capacity.MaxServices == 0 {
+		capacity.MaxServices = ipCapacityDefaults.MaxServices
+	}
+	if capacity.PodsCidr == "" {
+		capacity.PodsCidr = ipCapacityDefaults.PodsCidr
+	}
+	if capacity.ServicesCidr == "" {
+		capacity.ServicesCidr = ipCapacityDefaults.ServicesCidr
+	}
+	if err := capacity.validate(); err != nil {
+		return nil, err
+	}
+	fmt.Printf("[CONFIGURATION] - IP Capacity: %d pods per node & %d nodes per cluster (/%d pod range), %d services (/%d service range) per region.\n", capacity.MaxPodsPerNode, capacity.MaxNodes, prefixLength(capacity.podsSize), capacity.MaxServices, prefixLength(capacity.servicesSize))
+	return capacity, nil
+}
+
+// Function - Size the pod & service ranges and check they fit the supernets without overlapping the Subnets
+func (c *ipCapacityConfig) validate() error {
+	if c.MaxPodsPerNode < 8 || c.MaxPodsPerNode > 256 {
+		return fmt.Errorf("[CONFIGURATION] - IP Capacity: maxPodsPerNode (%d) must be between 8 and 256", c.MaxPodsPerNode)
+	}
+	if c.MaxNodes < 1 || c.MaxNodes > 15000 {
+		return fmt.Errorf("[CONFIGURATION] - IP Capacity: maxNodes (%d) must be between 1 and 15000", c.MaxNodes)
+	}
+	if c.MaxServices < 1 || c.MaxServices > 65536 {
+		return fmt.Errorf("[CONFIGURATION] - IP Capacity: maxServices (%d) must be between 1 and 65536", c.MaxServices)
+	}
+
+	// GKE reserves twice the pods per node, rounded up to a power of two, for each node; Services need at least a /27
+	c.podsSize = nextPowerOfTwo(uint32(2*c.MaxPodsPerNode)) * nextPowerOfTwo(uint32(c.MaxNodes))
+	c.servicesSize = nextPowerOfTwo(uint32(c.MaxServices))
+	if c.servicesSize < 32 {
+		c.servicesSize = 32
+	}
+	supernets := map[string]*net.IPNet{}
+	for _, supernet := range []struct {
+		name string
+		cidr string
+		size uint32
+	}{{"podsCidr", c.PodsCidr, c.podsSize}, {"servicesCidr", c.ServicesCidr, c.servicesSize}} {
+		_, network, err := net.ParseCIDR(supernet.cidr)
+		if err != nil || network.IP.To4() == nil {
+			return fmt.Errorf("[CONFIGURATION] - IP Capacity: %s '%s' must be an IPv4 CIDR range", supernet.name, supernet.cidr)
+		}
+		ones, _ := network.Mask.Size()
+		if uint64(supernet.size)*uint64(len(CloudRegions)) > uint64(1)<<(32-ones) {
+			return fmt.Errorf("[CONFIGURATION] - IP Capacity: %s '%s' cannot hold a /%d range for each of the %d Cloud Regions", supernet.name, supernet.cidr, prefixLength(supernet.size), len(CloudRegions))
+		}
+		supernets[supernet.name] = network
+	}
+
+	// The secondary ranges must not overlap each other or any Subnet's primary range
+	if rangesOverlap(supernets["podsCidr"], supernets["servicesCidr"]) {
+		return fmt.Errorf("[CONFIGURATION] - IP Capacity: podsCidr '%s' and servicesCidr '%s' overlap", c.PodsCidr, c.ServicesCidr)
+	}
+	for _, cloudRegion := range CloudRegions {
+		_, subnet, err := net.ParseCIDR(cloudRegion.SubnetIp)
+		if err != nil {
+			continue
+		}
+		for _, name := range []string{"podsCidr", "servicesCidr"} {
+			if rangesOverlap(supernets[name], subnet) {
+				return fmt.Errorf("[CONFIGURATION] - IP Capacity: %s '%s' overlaps the Subnet '%s' of Region %s", name, supernets[name], cloudRegion.SubnetIp, cloudRegion.Region)
+			}
+		}
+	}
+	return nil
+}
+
+// Function - The pod secondary range of the Cloud Region at 'index' in CloudRegions
+func (c *ipCapacityConfig) podsRange(index int) string {
+	return carveRange(c.PodsCidr, c.podsSize, index)
+}
+
+// Function - The service secondary range of the Cloud Region at 'index' in CloudRegions
+func (c *ipCapacityConfig) servicesRange(index int) string {
+	return carveRange(c.ServicesCidr, c.servicesSize, index)
+}
+
+// Function - The source ranges of the traffic between the clusters; The pod supernet & the Subnets of the enabled Cloud Regions
+func (c *ipCapacityConfig) clusterSourceRanges(regions []cloudRegion) []string {
+	ranges := []string{c.PodsCidr}
+	for _, region := range regions {
+		if region.Enabled {
+			ranges = append(ranges, region.SubnetIp)
+		}
+	}
+	return ranges
+}
+
+// Function - Warn when the node pool can grow past the nodes the pod range or the Subnet's primary range can hold
+func (c *ipCapacityConfig) checkNodePool(region string, subnetCidr string) {
+	nodes := nodePoolMaxNodeCount * gkeRegionZones
+	podRangeNodes := int(c.podsSize / nextPowerOfTwo(uint32(2*c.MaxPodsPerNode)))
+	if nodes > podRangeNodes {
+		fmt.Printf("[CONFIGURATION] - IP Capacity: WARNING - Region %s - The node pool can grow to %d nodes (MaxNodeCount %d in %d zones) but the /%d pod range only fits %d.\n", region, nodes, nodePoolMaxNodeCount, gkeRegionZones, prefixLength(c.podsSize), podRangeNodes)
+	}
+	if _, network, err := net.ParseCIDR(subnetCidr); err == nil {
+		ones, _ := network.Mask.Size()
+		// Google Cloud reserves 4 addresses in every primary range
+		subnetNodes := (1 << (32 - ones)) - 4
+		if nodes > subnetNodes {
+			fmt.Printf("[CONFIGURATION] - IP Capacity: WARNING - Region %s - The node pool can grow to %d nodes but the Subnet '%s' only fits %d.\n", region, nodes, subnetCidr, subnetNodes)
+		}
+	}
+}
+
+// Function - Whether two CIDR ranges share any address
+func rangesOverlap(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// Function - The 'index'th range of 'size' addresses in a supernet
+func carveRange(supernet string, size uint32, index int) string {
+	_, network, _ := net.ParseCIDR(supernet)
+	base := binary.BigEndian.Uint32(network.IP.To4()) + size*uint32(index)
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, base)
+	return fmt.Sprintf("%s/%d", ip, prefixLength(size))
+}
+
+// Function - The smallest power of two not less than n
+func nextPowerOfTwo(n uint32) uint32 {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << (32 - bits.LeadingZeros32(n-1))
+}
+
+// Function - The prefix length of a range of 'size' addresses (a power of two)
+func prefixLength(size uint32) int {
+	return 32 - bits.TrailingZeros32(size)
+}
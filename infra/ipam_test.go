@@ -0,0 +1,132 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNextPowerOfTwo(t *testing.T) {
+	tests := []struct {
+		input    uint32
+		expected uint32
+	}{
+		{0, 1},
+		{1, 1},
+		{2, 2},
+		{3, 4},
+		{16, 16},
+		{17, 32},
+		{220, 256},
+		{1 << 31, 1 << 31},
+	}
+	for _, test := range tests {
+		if got := nextPowerOfTwo(test.input); got != test.expected {
+			t.Errorf("nextPowerOfTwo(%d): got %d, expected %d", test.input, got, test.expected)
+		}
+	}
+}
+
+func TestPrefixLength(t *testing.T) {
+	tests := []struct {
+		input    uint32
+		expected int
+	}{
+		{1, 32},
+		{32, 27},
+		{1024, 22},
+		{4096, 20},
+	}
+	for _, test := range tests {
+		if got := prefixLength(test.input); got != test.expected {
+			t.Errorf("prefixLength(%d): got %d, expected %d", test.input, got, test.expected)
+		}
+	}
+}
+
+func TestCarveRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		supernet string
+		size     uint32
+		index    int
+		expected string
+	}{
+		{"first range", "10.64.0.0/12", 4096, 0, "10.64.0.0/20"},
+		{"second range", "10.64.0.0/12", 4096, 1, "10.64.16.0/20"},
+		{"crosses an octet", "10.96.0.0/16", 1024, 14, "10.96.56.0/22"},
+		{"supernet host bits ignored", "10.96.1.1/16", 32, 2, "10.96.0.64/27"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := carveRange(test.supernet, test.size, test.index); got != test.expected {
+				t.Errorf("got '%s', expected '%s'", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestIpCapacityConfigSizing(t *testing.T) {
+	tests := []struct {
+		name         string
+		capacity     ipCapacityConfig
+		podsSize     uint32
+		servicesSize uint32
+	}{
+		{"defaults", ipCapacityDefaults, 4096, 1024},
+		{"110 pods on 17 nodes", ipCapacityConfig{MaxPodsPerNode: 110, MaxNodes: 17, MaxServices: 1024, PodsCidr: "10.64.0.0/12", ServicesCidr: "10.96.0.0/16"}, 8192, 1024},
+		{"8 pods per node", ipCapacityConfig{MaxPodsPerNode: 8, MaxNodes: 1, MaxServices: 1, PodsCidr: "10.64.0.0/12", ServicesCidr: "10.96.0.0/16"}, 16, 32},
+		{"services rounded up", ipCapacityConfig{MaxPodsPerNode: 32, MaxNodes: 4, MaxServices: 1000, PodsCidr: "10.64.0.0/12", ServicesCidr: "10.96.0.0/16"}, 256, 1024},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			capacity := test.capacity
+			if err := capacity.validate(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if capacity.podsSize != test.podsSize || capacity.servicesSize != test.servicesSize {
+				t.Errorf("got pods %d & services %d, expected pods %d & services %d", capacity.podsSize, capacity.servicesSize, test.podsSize, test.servicesSize)
+			}
+		})
+	}
+}
+
+func TestIpCapacityConfigClusterSourceRanges(t *testing.T) {
+	capacity := ipCapacityDefaults
+	regions := []cloudRegion{
+		{Region: "us-central1", Enabled: true, SubnetIp: "10.128.50.0/24"},
+		{Region: "us-west1", Enabled: false, SubnetIp: "10.128.100.0/24"},
+		{Region: "us-east1", Enabled: true, SubnetIp: "10.128.150.0/24"},
+	}
+	expected := []string{"10.64.0.0/12", "10.128.50.0/24", "10.128.150.0/24"}
+	if got := capacity.clusterSourceRanges(regions); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("got '%v', expected '%v'", got, expected)
+	}
+}
+
+func TestIpCapacityConfigValidate(t *testing.T) {
+	valid := ipCapacityDefaults
+	tests := []struct {
+		name     string
+		modify   func(c *ipCapacityConfig)
+		expected string
+	}{
+		{"pods per node too low", func(c *ipCapacityConfig) { c.MaxPodsPerNode = 4 }, "maxPodsPerNode"},
+		{"invalid pods cidr", func(c *ipCapacityConfig) { c.PodsCidr = "10.64.0.0" }, "must be an IPv4 CIDR range"},
+		{"ipv6 services cidr", func(c *ipCapacityConfig) { c.ServicesCidr = "fd00::/64" }, "must be an IPv4 CIDR range"},
+		{"supernet too small", func(c *ipCapacityConfig) { c.PodsCidr = "10.64.0.0/17" }, "cannot hold a /20 range"},
+		{"pods & services overlap", func(c *ipCapacityConfig) { c.ServicesCidr = "10.64.0.0/16" }, "podsCidr '10.64.0.0/12' and servicesCidr '10.64.0.0/16' overlap"},
+		{"pods contain services", func(c *ipCapacityConfig) { c.PodsCidr = "10.96.0.0/12"; c.ServicesCidr = "10.100.0.0/16" }, "overlap"},
+		{"pods overlap a subnet", func(c *ipCapacityConfig) { c.PodsCidr = "10.128.0.0/12" }, "podsCidr '10.128.0.0/12' overlaps the Subnet"},
+		{"services overlap a subnet", func(c *ipCapacityConfig) { c.ServicesCidr = "10.129.0.0/16" }, "servicesCidr '10.129.0.0/16' overlaps the Subnet"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			capacity := valid
+			test.modify(&capacity)
+			err := capacity.validate()
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("got error '%v', expected it to contain '%s'", err, test.expected)
+			}
+		})
+	}
+}
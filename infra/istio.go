@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// Istio Helm Chart Repository
+const istioChartRepo = "https://istio-release.storage.googleapis.com/charts"
+
+// Istio Version installed when 'istioVersion' is not configured
+const istioDefaultVersion = "1.20.2"
+
+// Istio Canary Upgrade Stages; Each region is moved through the stages with one configuration change at a time.
+const (
+	// The canary revision of istiod is installed next to the stable one; Namespaces stay on the stable revision.
+	istioUpgradeStageInstall = "install"
+	// Namespaces & gateways are moved to the canary revision; The stable revision is kept for rollback.
+	istioUpgradeStageMigrate = "migrate"
+	// The stable revision is removed; Only the canary revision remains.
+	istioUpgradeStageComplete = "complete"
+)
+
+var istioVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+// An Istio Control Plane Revision, eg. Version '1.20.2' is installed as Revision '1-20-2'
+type istioRevision struct {
+	Version string
+	Name    string
+}
+
+// Istio Canary Upgrade; The target version and the stage each region has reached.
+type istioCanaryConfig struct {
+	Version string            `json:"version"`
+	Regions map[string]string `json:"regions"`
+}
+
+// Istio Configuration; The stable version for all regions and an optional canary upgrade.
+type istioConfig struct {
+	Stable istioRevision
+	Canary *istioRevision
+	Stages map[string]string
+}
+
+// The Istio Revisions for a single region; Every installed revision runs an istiod,
+// the Active revision is the one namespaces and gateways are labelled with.
+type istioRegionRevisions struct {
+	Active    istioRevision
+	Installed []istioRevision
+}
+
+// Function - Read & Validate the Istio Version & Canary Upgrade configuration
+func loadIstioConfig(cfg *config.Config) (*istioConfig, error) {
+	version := cfg.Get("istioVersion")
+	if version == "" {
+		version = istioDefaultVersion
+	}
+	if !istioVersionPattern.MatchString(version) {
+		return nil, fmt.Errorf("[CONFIGURATION] - Istio Version: '%s' must be a full release version, eg. '%s'", version, istioDefaultVersion)
+	}
+	istio := &istioConfig{
+		Stable: newIstioRevision(version),
+		Stages: map[string]string{},
+	}
+	fmt.Printf("[CONFIGURATION] - Istio: Version %s (Revision '%s') will be installed.\n", istio.Stable.Version, istio.Stable.Name)
+
+	canary := istioCanaryConfig{}
+	if err := cfg.GetObject("istioCanary", &canary); err != nil {
+		return nil, fmt.Errorf("[CONFIGURATION] - Istio Canary: %w", err)
+	}
+	if canary.Version == "" {
+		return istio, nil
+	}
+	if !istioVersionPattern.MatchString(canary.Version) {
+		return nil, fmt.Errorf("[CONFIGURATION] - Istio Canary Version: '%s' must be a full release version, eg. '%s'", canary.Version, istioDefaultVersion)
+	}
+	if canary.Version == version {
+		return nil, fmt.Errorf("[CONFIGURATION] - Istio Canary Version: '%s' is already the stable version", canary.Version)
+	}
+	for region, stage := range canary.Regions {
+		if !isCloudRegion(region) {
+			return nil, fmt.Errorf("[CONFIGURATION] - Istio Canary: stage for unknown region '%s'", region)
+		}
+		switch stage {
+		case istioUpgradeStageInstall, istioUpgradeStageMigrate, istioUpgradeStageComplete:
+			fmt.Printf("[CONFIGURATION] - Istio Canary: Region %s is at stage '%s' of the upgrade to %s.\n", region, stage, canary.Version)
+		default:
+			return nil, fmt.Errorf("[CONFIGURATION] - Istio Canary: Region %s has unknown stage '%s'; Must be one of '%s', '%s' or '%s'", region, stage, istioUpgradeStageInstall, istioUpgradeStageMigrate, istioUpgradeStageComplete)
+		}
+	}
+	revision := newIstioRevision(canary.Version)
+	istio.Canary = &revision
+	istio.Stages = canary.Regions
+	return istio, nil
+}
+
+// Function - Create an Istio Revision from a Version
+func newIstioRevision(version string) istioRevision {
+	return istioRevision{
+		Version: version,
+		Name:    strings.ReplaceAll(version, ".", "-"),
+	}
+}
+
+// Function - Resolve which Istio Revisions are installed (and active) in a region
+func (c *istioConfig) regionRevisions(region string) istioRegionRevisions {
+	if c.Canary == nil {
+		return istioRegionRevisions{Active: c.Stable, Installed: []istioRevision{c.Stable}}
+	}
+	switch c.Stages[region] {
+	case istioUpgradeStageInstall:
+		return istioRegionRevisions{Active: c.Stable, Installed: []istioRevision{c.Stable, *c.Canary}}
+	case istioUpgradeStageMigrate:
+		return istioRegionRevisions{Active: *c.Canary, Installed: []istioRevision{c.Stable, *c.Canary}}
+	case istioUpgradeStageComplete:
+		return istioRegionRevisions{Active: *c.Canary, Installed: []istioRevision{*c.Canary}}
+	}
+	return istioRegionRevisions{Active: c.Stable, Installed: []istioRevision{c.Stable}}
+}
+
+// Function - The newest installed revision; Used for the cluster wide Istio base chart (CRDs)
+func (r istioRegionRevisions) latest() istioRevision {
+	return r.Installed[len(r.Installed)-1]
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/compute"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Global Load Balancer Configuration; Used by the AutoNeg Ingress Mode, where the AutoNeg controller
+// registers the Istio Ingress Gateway NEGs with the Backend Service.
+type globalLoadBalancerArgs struct {
+	ProjectId          string
+	ResourceNamePrefix string
+	Names              *stackNamer
+	Domain             string
+	SSL                bool
+	BackendProtocol    string
+	Address            *compute.GlobalAddress
+	// Optional; Set when the Load Balancer is dual-stack
+	AddressIPv6 *compute.GlobalAddress
+	// The Application Catalog; Builds the URL Map Host Rules & Path Matchers
+	Applications []*application
+	Dependencies []pulumi.Resource
+}
+
+// Function - Create the Global Load Balancer (Health Check, Backend Service, URL Maps, Target Proxies & Forwarding Rules)
+func createGlobalLoadBalancer(ctx *pulumi.Context, lb *globalLoadBalancerArgs) (*compute.BackendService, error) {
+	// Create Health Check (Network Endpoints within Load Balancer); Probes the readiness endpoint of the Istio Ingress Gateway
+	// status port, so the check does not depend on the Backend Protocol or on an Application serving '/'
+	resourceName := fmt.Sprintf("%s-glb-http-hc", lb.ResourceNamePrefix)
+	gcpGLBHealthCheck, err := compute.NewHealthCheck(ctx, resourceName, &compute.HealthCheckArgs{
+		Project:            pulumi.String(lb.ProjectId),
+		CheckIntervalSec:   pulumi.Int(1),
+		Description:        pulumi.String("HTTP Health Check - Istio Ingress Gateway Readiness"),
+		HealthyThreshold:   pulumi.Int(4),
+		TimeoutSec:         pulumi.Int(1),
+		UnhealthyThreshold: pulumi.Int(5),
+		HttpHealthCheck: &compute.HealthCheckHttpHealthCheckArgs{
+			PortSpecification: pulumi.String("USE_FIXED_PORT"),
+			Port:              pulumi.Int(ingressGatewayStatusPort),
+			RequestPath:       pulumi.String(ingressGatewayReadinessPath),
+			ProxyHeader:       pulumi.String("NONE"),
+		},
+	}, pulumi.DependsOn(lb.Dependencies))
+	if err != nil {
+		return nil, err
+	}
+
+	// Create Global Load Balancer Backend Service
+	var backendServiceBackendArray = compute.BackendServiceBackendArray{}
+	resourceName = fmt.Sprintf("%s-glb-bes", lb.ResourceNamePrefix)
+	gcpBackendService, err := compute.NewBackendService(ctx, resourceName, &compute.BackendServiceArgs{
+		Project:     pulumi.String(lb.ProjectId),
+		Name:        lb.Names.ComputeName("bes"),
+		Description: pulumi.String("GKE At Scale - Global Load Balancer - Backend Service"),
+		Protocol:    pulumi.String(lb.BackendProtocol),
+		CdnPolicy: &compute.BackendServiceCdnPolicyArgs{
+			ClientTtl:  pulumi.Int(5),
+			DefaultTtl: pulumi.Int(5),
+			MaxTtl:     pulumi.Int(5),
+		},
+		ConnectionDrainingTimeoutSec: pulumi.Int(10),
+		Backends:                     backendServiceBackendArray,
+		HealthChecks:                 gcpGLBHealthCheck.ID(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The Domain & every Application host; Each is on the Managed SSL Certificate & redirected to HTTPS
+	hosts := pulumi.ToStringArray(applicationHosts(lb.Applications, lb.Domain))
+
+	// Create Managed SSL Certificate
+	if lb.SSL {
+		resourceName = fmt.Sprintf("%s-glb-ssl-cert", lb.ResourceNamePrefix)
+		gcpGLBManagedSSLCert, err := compute.NewManagedSslCertificate(ctx, resourceName, &compute.ManagedSslCertificateArgs{
+			Project:     pulumi.String(lb.ProjectId),
+			Name:        lb.Names.ComputeName("glb-ssl-cert"),
+			Description: pulumi.String("GKE at Scale - Global Load Balancer - Managed SSL Certificate"),
+			Type:        pulumi.String("MANAGED"),
+			Managed: &compute.ManagedSslCertificateManagedArgs{
+				Domains: hosts,
+			},
+		}, pulumi.DependsOn(lb.Dependencies))
+		if err != nil {
+			return nil, err
+		}
+
+		// Create URL Map; Routing the Application Catalog
+		hostRules, pathMatchers := urlMapRouting(lb.Applications, lb.Domain, gcpBackendService.SelfLink)
+		resourceName = fmt.Sprintf("%s-glb-url-map-https-domain", lb.ResourceNamePrefix)
+		gcpGLBURLMapHTTPS, err := compute.NewURLMap(ctx, resourceName, &compute.URLMapArgs{
+			Project:        pulumi.String(lb.ProjectId),
+			Name:           lb.Names.ComputeName("glb-urlmap-https"),
+			Description:    pulumi.String("GKE At Scale - Global Load Balancer - HTTPS URL Map"),
+			HostRules:      hostRules,
+			PathMatchers:   pathMatchers,
+			DefaultService: gcpBackendService.SelfLink,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Create Target HTTPS Proxy
+		resourceName = fmt.Sprintf("%s-glb-https-proxy", lb.ResourceNamePrefix)
+		gcpGLBTargetHTTPSProxy, err := compute.NewTargetHttpsProxy(ctx, resourceName, &compute.TargetHttpsProxyArgs{
+			Project: pulumi.String(lb.ProjectId),
+			Name:    lb.Names.ComputeName("glb-https-proxy"),
+			UrlMap:  gcpGLBURLMapHTTPS.SelfLink,
+			SslCertificates: pulumi.StringArray{
+				gcpGLBManagedSSLCert.SelfLink,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Global Load Balancer Forwarding Rule for HTTPS Traffic.
+		resourceName = fmt.Sprintf("%s-glb-https-fwd-rule", lb.ResourceNamePrefix)
+		_, err = compute.NewGlobalForwardingRule(ctx, resourceName, &compute.GlobalForwardingRuleArgs{
+			Project:             pulumi.String(lb.ProjectId),
+			Target:              gcpGLBTargetHTTPSProxy.SelfLink,
+			IpAddress:           lb.Address.SelfLink,
+			PortRange:           pulumi.String("443"),
+			LoadBalancingScheme: pulumi.String("EXTERNAL"),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Global Load Balancer Forwarding Rule for HTTPS Traffic over IPv6.
+		if lb.AddressIPv6 != nil {
+			resourceName = fmt.Sprintf("%s-glb-https-ipv6-fwd-rule", lb.ResourceNamePrefix)
+			_, err = compute.NewGlobalForwardingRule(ctx, resourceName, &compute.GlobalForwardingRuleArgs{
+				Project:             pulumi.String(lb.ProjectId),
+				Target:              gcpGLBTargetHTTPSProxy.SelfLink,
+				IpAddress:           lb.AddressIPv6.SelfLink,
+				PortRange:           pulumi.String("443"),
+				LoadBalancingScheme: pulumi.String("EXTERNAL"),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	}
+
+	// Create URL Maps
+	gcpGLBURLMapHTTP := &compute.URLMap{}
+	if lb.Domain == "" {
+		// Create URL Map - When No Domain is provided - HTTP Traffic; Routing the Application Catalog
+		hostRules, pathMatchers := urlMapRouting(lb.Applications, "*", gcpBackendService.SelfLink)
+		resourceName = fmt.Sprintf("%s-glb-url-map-http-no-domain", lb.ResourceNamePrefix)
+		gcpGLBURLMapHTTP, err = compute.NewURLMap(ctx, resourceName, &compute.URLMapArgs{
+			Project:        pulumi.String(lb.ProjectId),
+			Name:           lb.Names.ComputeName("glb-urlmap-http"),
+			Description:    pulumi.String("GKE At Scale - Global Load Balancer - HTTP URL Map"),
+			HostRules:      hostRules,
+			PathMatchers:   pathMatchers,
+			DefaultService: gcpBackendService.SelfLink,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+	} else {
+		// Create URL Map - When Domain is provided - HTTP Traffic.
+		resourceName = fmt.Sprintf("%s-glb-url-map-http-domain", lb.ResourceNamePrefix)
+		gcpGLBURLMapHTTP, err = compute.NewURLMap(ctx, resourceName, &compute.URLMapArgs{
+			Project:     pulumi.String(lb.ProjectId),
+			Name:        lb.Names.ComputeName("glb-urlmap-http"),
+			Description: pulumi.String("GKE At Scale - Global Load Balancer - HTTP URL Map"),
+			HostRules: &compute.URLMapHostRuleArray{
+				&compute.URLMapHostRuleArgs{
+					Hosts:       hosts,
+					PathMatcher: pulumi.String("all-paths"),
+					Description: pulumi.String("Default Route All Paths"),
+				},
+			},
+			PathMatchers: &compute.URLMapPathMatcherArray{
+				&compute.URLMapPathMatcherArgs{
+					Name:           pulumi.String("all-paths"),
+					DefaultService: gcpBackendService.SelfLink,
+					PathRules: &compute.URLMapPathMatcherPathRuleArray{
+						&compute.URLMapPathMatcherPathRuleArgs{
+							Paths: pulumi.StringArray{
+								pulumi.String("/*"),
+							},
+							UrlRedirect: &compute.URLMapPathMatcherPathRuleUrlRedirectArgs{
+								StripQuery: pulumi.Bool(false),
+								// If Domain Configured and SSL Enabled
+								HttpsRedirect: pulumi.Bool(lb.SSL),
+							},
+						},
+					},
+				},
+			},
+			DefaultService: gcpBackendService.SelfLink,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create Target HTTP Proxy
+	resourceName = fmt.Sprintf("%s-glb-http-proxy", lb.ResourceNamePrefix)
+	gcpGLBTargetHTTPProxy, err := compute.NewTargetHttpProxy(ctx, resourceName, &compute.TargetHttpProxyArgs{
+		Project: pulumi.String(lb.ProjectId),
+		Name:    lb.Names.ComputeName("glb-http-proxy"),
+		UrlMap:  gcpGLBURLMapHTTP.SelfLink,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create HTTP Global Forwarding Rule
+	resourceName = fmt.Sprintf("%s-glb-http-fwd-rule", lb.ResourceNamePrefix)
+	_, err = compute.NewGlobalForwardingRule(ctx, resourceName, &compute.GlobalForwardingRuleArgs{
+		Project:             pulumi.String(lb.ProjectId),
+		Target:              gcpGLBTargetHTTPProxy.SelfLink,
+		IpAddress:           lb.Address.SelfLink,
+		PortRange:           pulumi.String("80"),
+		LoadBalancingScheme: pulumi.String("EXTERNAL"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create HTTP Global Forwarding Rule for IPv6
+	if lb.AddressIPv6 != nil {
+		resourceName = fmt.Sprintf("%s-glb-http-ipv6-fwd-rule", lb.ResourceNamePrefix)
+		_, err = compute.NewGlobalForwardingRule(ctx, resourceName, &compute.GlobalForwardingRuleArgs{
+			Project:             pulumi.String(lb.ProjectId),
+			Target:              gcpGLBTargetHTTPProxy.SelfLink,
+			IpAddress:           lb.AddressIPv6.SelfLink,
+			PortRange:           pulumi.String("80"),
+			LoadBalancingScheme: pulumi.String("EXTERNAL"),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return gcpBackendService, nil
+}
@@ -3,9 +3,12 @@ package main
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/compute"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/container"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/dns"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/gkehub"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/iam"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/projects"
 	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/serviceaccount"
@@ -23,7 +26,7 @@ type cloudRegion struct {
 	Region         string
 	SubnetIp       string
 	GKECluster     *container.Cluster
-	GKEClusterName string
+	GKEClusterName pulumi.StringOutput
 }
 
 var CloudRegions = []cloudRegion{
@@ -119,10 +122,23 @@ var CloudRegions = []cloudRegion{
 	},
 }
 
-// Declare an Array of API's To Enable.
-var GCPServices = []string{
-	"compute.googleapis.com",
-	"container.googleapis.com",
+// Declare the Minimum IAM Roles required by the GKE Node Service Account.
+var GKENodeServiceAccountRoles = []string{
+	"roles/logging.logWriter",
+	"roles/monitoring.metricWriter",
+	"roles/monitoring.viewer",
+	"roles/stackdriver.resourceMetadata.writer",
+	"roles/artifactregistry.reader",
+}
+
+// Declare the OAuth Scopes granted to GKE Nodes; Access is governed by the IAM Roles above.
+var GKENodeOauthScopes = []string{
+	"https://www.googleapis.com/auth/devstorage.read_only",
+	"https://www.googleapis.com/auth/logging.write",
+	"https://www.googleapis.com/auth/monitoring",
+	"https://www.googleapis.com/auth/servicecontrol",
+	"https://www.googleapis.com/auth/service.management.readonly",
+	"https://www.googleapis.com/auth/trace.append",
 }
 
 func main() {
@@ -130,7 +146,13 @@ func main() {
 
 		// Global Variables
 		var SSL bool
-		gcpDependencies := []pulumi.Resource{}
+		var IPv6 bool
+
+		// Google API's required by the enabled features; The VPC Network, GKE Clusters & Service Accounts are always required
+		gcpServices := newServiceSet()
+		gcpServices.require(ComputeServices...)
+		gcpServices.require(GKEServices...)
+		gcpServices.require(IAMServices...)
 
 		// Instanciate Pulumi Configuration
 		cfg := config.New(ctx, "")
@@ -152,6 +174,18 @@ func main() {
 			fmt.Printf("[CONFIGURATION] - Prefix: %s has been provided; All Google Cloud resource names will be prefixed.\n", resourceNamePrefix)
 		}
 
+		// Build Google Cloud Resource Names from the Prefix, Stack & a Suffix generated once (or configured)
+		nameSuffix := cfg.Get("nameSuffix")
+		names, err := newStackNamer(ctx, resourceNamePrefix, nameSuffix)
+		if err != nil {
+			return err
+		}
+		if nameSuffix != "" {
+			fmt.Printf("[CONFIGURATION] - Name Suffix: %s; Google Cloud resource names are unique to stack '%s'.\n", nameSuffix, ctx.Stack())
+		} else {
+			fmt.Printf("[CONFIGURATION] - Name Suffix: Generated once & kept in the state; Google Cloud resource names are unique to stack '%s'.\n", ctx.Stack())
+		}
+
 		// Review Domain & SSL Configuration
 		domain := cfg.Get("domainName")
 		if domain != "" {
@@ -163,91 +197,315 @@ func main() {
 			SSL = false
 		}
 
-		// Enable Google API's on the Specified Project.
-		for _, Service := range GCPServices {
-			resourceName := fmt.Sprintf("%s-project-service-%s", resourceNamePrefix, Service)
-			gcpService, err := projects.NewService(ctx, resourceName, &projects.ServiceArgs{
-				DisableDependentServices: pulumi.Bool(true),
-				Project:                  pulumi.String(gcpProjectId),
-				Service:                  pulumi.String(Service),
-				DisableOnDestroy:         pulumi.Bool(false),
-			})
-			if err != nil {
-				return err
+		// Review Backend Protocol Configuration (Global Load Balancer to Istio Ingress Gateway)
+		backendProtocol := cfg.Get("backendProtocol")
+		backendPort := 80
+		switch backendProtocol {
+		case "", "HTTP":
+			backendProtocol = "HTTP"
+		case "HTTPS", "HTTP2":
+			backendPort = 443
+			fmt.Printf("[CONFIGURATION] - Backend Protocol: %s; Traffic from the Global Load Balancer to the Istio Ingress Gateways will be encrypted.\n", backendProtocol)
+		default:
+			return fmt.Errorf("[CONFIGURATION] - Backend Protocol: '%s' must be one of 'HTTP', 'HTTPS' or 'HTTP2'", backendProtocol)
+		}
+		backendTLS := backendPort == 443
+
+		// Review Ingress Mode Configuration
+		ingressMode := cfg.Get("ingressMode")
+		switch ingressMode {
+		case "", ingressModeAutoneg:
+			ingressMode = ingressModeAutoneg
+		case ingressModeMultiClusterGateway:
+			fmt.Printf("[CONFIGURATION] - Ingress Mode: %s; Clusters will be registered to the Fleet and served by a GKE Multi-Cluster Gateway.\n", ingressMode)
+			if backendProtocol != "HTTP" {
+				return fmt.Errorf("[CONFIGURATION] - Backend Protocol: '%s' is only supported with Ingress Mode '%s'", backendProtocol, ingressModeAutoneg)
+			}
+			gcpServices.require(MultiClusterGatewayServices...)
+		default:
+			return fmt.Errorf("[CONFIGURATION] - Ingress Mode: '%s' must be '%s' or '%s'", ingressMode, ingressModeAutoneg, ingressModeMultiClusterGateway)
+		}
+
+		// Review IPv6 Configuration
+		IPv6 = cfg.GetBool("enableIpv6")
+		if IPv6 && ingressMode != ingressModeAutoneg {
+			return fmt.Errorf("[CONFIGURATION] - IPv6: The dual-stack Global Load Balancer is only supported with Ingress Mode '%s'", ingressModeAutoneg)
+		}
+		if IPv6 {
+			fmt.Printf("[CONFIGURATION] - IPv6: Enabled; The Global Load Balancer will be dual-stack (IPv4 & IPv6).\n")
+		}
+
+		// Review DNS Configuration
+		dnsManagedZone := cfg.Get("dnsManagedZone")
+		if dnsManagedZone != "" {
+			if domain == "" {
+				return fmt.Errorf("[CONFIGURATION] - DNS Managed Zone: '%s' requires a Domain (domainName) to be configured", dnsManagedZone)
+			}
+			fmt.Printf("[CONFIGURATION] - DNS: Managed Zone '%s' has been provided; DNS records for '%s' will be managed by this deployment.\n", dnsManagedZone, domain)
+			gcpServices.require(DNSServices...)
+		}
+
+		// Review Application Workload Identity Configuration
+		appIdentities, err := loadAppIdentities(cfg)
+		if err != nil {
+			return err
+		}
+
+		// Review Application Catalog Configuration
+		applications, err := loadApplications(cfg)
+		if err != nil {
+			return err
+		}
+		for _, app := range applications {
+			if app.Canary != nil && ingressMode == ingressModeMultiClusterGateway {
+				fmt.Printf("[CONFIGURATION] - Application: '%s' - The Multi-Cluster Gateway bypasses the Istio VirtualService; Canary weights only apply to traffic inside the mesh.\n", app.Name)
+			}
+		}
+
+		// Review Istio Version Configuration
+		istio, err := loadIstioConfig(cfg)
+		if err != nil {
+			return err
+		}
+
+		// Review Istio Multi-Cluster Mesh Configuration
+		mesh, err := loadMeshConfig(cfg, resourceNamePrefix)
+		if err != nil {
+			return err
+		}
+
+		// Review Istio Mesh Security Configuration
+		meshSecurity, err := loadMeshSecurityConfig(cfg)
+		if err != nil {
+			return err
+		}
+
+		// The Multi-Cluster Gateway sends plain-text traffic from the Google Front Ends directly to the application pods
+		if ingressMode == ingressModeMultiClusterGateway && (meshSecurity.MtlsMode == "STRICT" || meshSecurity.DefaultDeny) {
+			return fmt.Errorf("[CONFIGURATION] - Ingress Mode: '%s' requires 'istioMtlsMode' PERMISSIVE and 'istioDefaultDeny' false so the Gateway can reach the application pods", ingressModeMultiClusterGateway)
+		}
+
+		// Review Istio Ingress Gateway Configuration
+		ingressGateway, err := loadIngressGatewayConfig(cfg)
+		if err != nil {
+			return err
+		}
+		ingressGatewayNamespace := cfg.Get("ingressGatewayNamespace")
+		if ingressGatewayNamespace == "" {
+			ingressGatewayNamespace = ingressGatewayDefaultNamespace
+		}
+		fmt.Printf("[CONFIGURATION] - Ingress Gateway: Namespace '%s'.\n", ingressGatewayNamespace)
+
+		// Review Application External Service Configuration (Per-cluster LoadBalancer Services for debugging)
+		appExternalService := cfg.GetBool("appExternalService")
+		if appExternalService {
+			fmt.Printf("[CONFIGURATION] - Application External Service: Enabled; Every cluster exposes the applications on its own public IP, bypassing the Global Load Balancer.\n")
+		}
+
+		// Review Application Values Configuration (Stack-wide defaults & per-region overrides)
+		appValues, err := loadAppValuesConfig(cfg)
+		if err != nil {
+			return err
+		}
+
+		// Review Application Scaling Configuration
+		appScaling, err := loadAppScalingConfig(cfg)
+		if err != nil {
+			return err
+		}
+
+		// Review Application Namespace Baseline Configuration
+		namespaceBaseline, err := loadNamespaceBaselineConfig(cfg)
+		if err != nil {
+			return err
+		}
+		baselineNamespaces := []string{ingressGatewayNamespace}
+		for _, app := range applications {
+			baselineNamespaces = append(baselineNamespaces, app.Namespace)
+		}
+		for _, identity := range appIdentities {
+			baselineNamespaces = append(baselineNamespaces, identity.Namespace)
+		}
+		if err := namespaceBaseline.checkNamespaces(baselineNamespaces); err != nil {
+			return err
+		}
+
+		// Review GKE Datapath Configuration
+		datapath, err := loadDatapathConfig(cfg)
+		if err != nil {
+			return err
+		}
+		if !datapath.enforcesNetworkPolicies() && *namespaceBaseline.DefaultDeny {
+			fmt.Printf("[CONFIGURATION] - Datapath: '%s' does not enforce NetworkPolicies; Set 'datapath' to '%s' for the Namespace Baseline to take effect.\n", datapath.Datapath, datapathAdvanced)
+		}
+
+		// Review Cluster IP Capacity Configuration
+		ipCapacity, err := loadIpCapacityConfig(cfg)
+		if err != nil {
+			return err
+		}
+		for _, cloudRegion := range CloudRegions {
+			if cloudRegion.Enabled {
+				ipCapacity.checkNodePool(cloudRegion.Region, cloudRegion.SubnetIp)
 			}
-			// Append API Enablement Resources to a Depenancies Array
-			gcpDependencies = append(gcpDependencies, gcpService)
+		}
+
+		// Review Application Traffic Policy Configuration
+		appTrafficPolicy, err := loadAppTrafficPolicyConfig(cfg)
+		if err != nil {
+			return err
+		}
+
+		// Review AutoNeg Controller Configuration
+		autoneg, err := loadAutonegConfig(cfg)
+		if err != nil {
+			return err
+		}
+
+		// Review Fleet & Managed Service Mesh Configuration
+		fleet, err := loadFleetConfig(cfg)
+		if err != nil {
+			return err
+		}
+		if ingressMode == ingressModeMultiClusterGateway {
+			// The Multi-Cluster Gateway routes to Clusters through their Fleet Memberships
+			fleet.Enabled = true
+		}
+		if fleet.ManagedMesh && mesh.Enabled {
+			return fmt.Errorf("[CONFIGURATION] - Istio Control Plane: '%s' manages multi-cluster discovery itself; 'istioMultiCluster' must not be set", istioControlPlaneManaged)
+		}
+		if fleet.ManagedMesh && istio.Canary != nil {
+			fmt.Printf("[CONFIGURATION] - Istio Canary: Ignored; The managed Cloud Service Mesh control plane is upgraded by Google.\n")
+		}
+		gcpServices.require(fleet.services()...)
+
+		// Enable Google API's on the Specified Project.
+		err = gcpServices.enable(ctx, resourceNamePrefix, gcpProjectId)
+		if err != nil {
+			return err
+		}
+		computeServices, err := gcpServices.dependencies(ComputeServices...)
+		if err != nil {
+			return err
+		}
+		iamServices, err := gcpServices.dependencies(IAMServices...)
+		if err != nil {
+			return err
+		}
+		gkeServices, err := gcpServices.dependencies(GKEServices...)
+		if err != nil {
+			return err
 		}
 
 		// Create Global Load Balancer Static IP Address
 		resourceName := fmt.Sprintf("%s-glb-ip-address", resourceNamePrefix)
 		gcpGlobalAddress, err := compute.NewGlobalAddress(ctx, resourceName, &compute.GlobalAddressArgs{
 			Project:     pulumi.String(gcpProjectId),
-			Name:        pulumi.String(resourceName),
+			Name:        names.ComputeName("glb-ip-address"),
 			AddressType: pulumi.String("EXTERNAL"),
 			IpVersion:   pulumi.String("IPV4"),
 			Description: pulumi.String("GKE At Scale - Global Load Balancer - Static IP Address"),
-		}, pulumi.DependsOn(gcpDependencies))
+		}, pulumi.DependsOn(computeServices))
 		if err != nil {
 			return err
 		}
 		// Export the Global Load Balancer IP Address
 		ctx.Export(resourceName, gcpGlobalAddress.Address)
 
-		// Create Custom IAM Role that will be used by the AutoNeg Kubernetes Deployment
-		// This Role allows the AutoNeg CRD to link the Istio Ingress Gateway Service Ip to Load Balancer NEGs
-		resourceName = fmt.Sprintf("%s-iam-custom-role-autoneg", resourceNamePrefix)
-		gcpIAMRoleAutoNeg, err := projects.NewIAMCustomRole(ctx, resourceName, &projects.IAMCustomRoleArgs{
-			Project:     pulumi.String(gcpProjectId),
-			Description: pulumi.String("Custom IAM Role - GKE AutoNeg"),
-			Permissions: pulumi.StringArray{
-				pulumi.String("compute.backendServices.get"),
-				pulumi.String("compute.backendServices.update"),
-				pulumi.String("compute.regionBackendServices.get"),
-				pulumi.String("compute.regionBackendServices.update"),
-				pulumi.String("compute.networkEndpointGroups.use"),
-				pulumi.String("compute.healthChecks.useReadOnly"),
-				pulumi.String("compute.regionHealthChecks.useReadOnly"),
-			},
+		// Create Global Load Balancer Static IPv6 Address
+		var gcpGlobalAddressIPv6 *compute.GlobalAddress
+		if IPv6 {
+			resourceName = fmt.Sprintf("%s-glb-ipv6-address", resourceNamePrefix)
+			gcpGlobalAddressIPv6, err = compute.NewGlobalAddress(ctx, resourceName, &compute.GlobalAddressArgs{
+				Project:     pulumi.String(gcpProjectId),
+				Name:        names.ComputeName("glb-ipv6-address"),
+				AddressType: pulumi.String("EXTERNAL"),
+				IpVersion:   pulumi.String("IPV6"),
+				Description: pulumi.String("GKE At Scale - Global Load Balancer - Static IPv6 Address"),
+			}, pulumi.DependsOn(computeServices))
+			if err != nil {
+				return err
+			}
+			// Export the Global Load Balancer IPv6 Address
+			ctx.Export(resourceName, gcpGlobalAddressIPv6.Address)
+		}
 
-			RoleId: pulumi.String(fmt.Sprintf("%s_iam_role_autoneg_system", resourceNamePrefix)),
-			Title:  pulumi.String("GKE at Scale - AutoNEG"),
-		})
-		if err != nil {
-			return err
+		// Create DNS Records for the Domain pointing at the Global Load Balancer
+		if dnsManagedZone != "" {
+			dnsServices, err := gcpServices.dependencies(DNSServices...)
+			if err != nil {
+				return err
+			}
+			resourceName = fmt.Sprintf("%s-dns-record-a", resourceNamePrefix)
+			_, err = dns.NewRecordSet(ctx, resourceName, &dns.RecordSetArgs{
+				Project:     pulumi.String(gcpProjectId),
+				ManagedZone: pulumi.String(dnsManagedZone),
+				Name:        pulumi.String(fmt.Sprintf("%s.", domain)),
+				Type:        pulumi.String("A"),
+				Ttl:         pulumi.Int(300),
+				Rrdatas: pulumi.StringArray{
+					gcpGlobalAddress.Address,
+				},
+			}, pulumi.DependsOn(dnsServices))
+			if err != nil {
+				return err
+			}
+
+			if IPv6 {
+				resourceName = fmt.Sprintf("%s-dns-record-aaaa", resourceNamePrefix)
+				_, err = dns.NewRecordSet(ctx, resourceName, &dns.RecordSetArgs{
+					Project:     pulumi.String(gcpProjectId),
+					ManagedZone: pulumi.String(dnsManagedZone),
+					Name:        pulumi.String(fmt.Sprintf("%s.", domain)),
+					Type:        pulumi.String("AAAA"),
+					Ttl:         pulumi.Int(300),
+					Rrdatas: pulumi.StringArray{
+						gcpGlobalAddressIPv6.Address,
+					},
+				}, pulumi.DependsOn(dnsServices))
+				if err != nil {
+					return err
+				}
+			}
 		}
 
-		// Create Google Cloud Service Account
+		// Create GKE Node Service Account
 		resourceName = fmt.Sprintf("%s-service-account", resourceNamePrefix)
 		gcpServiceAccount, err := serviceaccount.NewAccount(ctx, resourceName, &serviceaccount.AccountArgs{
 			Project:     pulumi.String(gcpProjectId),
-			AccountId:   pulumi.String("svc-gke-at-scale-admin"),
-			DisplayName: pulumi.String("GKE at Scale - Admin Service Account"),
-		})
+			AccountId:   names.ServiceAccountId("svc-gke-nodes"),
+			DisplayName: pulumi.String(fmt.Sprintf("GKE at Scale - %s - Node Service Account", resourceNamePrefix)),
+		}, pulumi.DependsOn(iamServices))
 		if err != nil {
 			return err
 		}
 
-		// Create AutoNeg Service Account
-		resourceName = fmt.Sprintf("%s-service-account-autoneg", resourceNamePrefix)
-		gcpServiceAccountAutoNeg, err := serviceaccount.NewAccount(ctx, resourceName, &serviceaccount.AccountArgs{
-			Project:     pulumi.String(gcpProjectId),
-			AccountId:   pulumi.String("autoneg-system"),
-			DisplayName: pulumi.String("GKE at Scale - AutoNEG Service Account"),
-		})
-		if err != nil {
-			return err
+		// Grant the GKE Node Service Account the Minimum Roles it requires.
+		gcpServiceAccountRoles := []pulumi.Resource{}
+		for _, role := range GKENodeServiceAccountRoles {
+			resourceName = fmt.Sprintf("%s-iam-member-gke-nodes-%s", resourceNamePrefix, strings.TrimPrefix(role, "roles/"))
+			gcpServiceAccountRole, err := projects.NewIAMMember(ctx, resourceName, &projects.IAMMemberArgs{
+				Project: pulumi.String(gcpProjectId),
+				Role:    pulumi.String(role),
+				Member:  pulumi.Sprintf("serviceAccount:%s", gcpServiceAccount.Email),
+			}, pulumi.DependsOn(iamServices))
+			if err != nil {
+				return err
+			}
+			gcpServiceAccountRoles = append(gcpServiceAccountRoles, gcpServiceAccountRole)
 		}
 
-		// Create AutoNEG IAM Role Binding to link AutoNeg Service Account to Custom Role.
-		resourceName = fmt.Sprintf("%s-iam-role-binding-autoneg", resourceNamePrefix)
-		_, err = projects.NewIAMBinding(ctx, resourceName, &projects.IAMBindingArgs{
-			Members: pulumi.StringArray{
-				pulumi.String(fmt.Sprintf("serviceAccount:autoneg-system@%s.iam.gserviceaccount.com", gcpProjectId)),
-			},
-			Project: pulumi.String(gcpProjectId),
-			Role:    gcpIAMRoleAutoNeg.ID(),
-		}, pulumi.DependsOn([]pulumi.Resource{gcpServiceAccountAutoNeg}))
+		// Create AutoNeg Service Account & Custom Role (AutoNeg Ingress Mode)
+		var gcpServiceAccountAutoNeg *serviceaccount.Account
+		if ingressMode == ingressModeAutoneg {
+			gcpServiceAccountAutoNeg, err = createAutonegServiceAccount(ctx, names, gcpProjectId, resourceNamePrefix, pulumi.DependsOn(iamServices))
+			if err != nil {
+				return err
+			}
+		}
+
+		// Create Google Cloud Service Accounts for Application Workload Identities
+		err = createAppIdentityServiceAccounts(ctx, names, gcpProjectId, resourceNamePrefix, appIdentities, pulumi.DependsOn(iamServices))
 		if err != nil {
 			return err
 		}
@@ -259,8 +517,8 @@ func main() {
 			Description:            pulumi.String("GKE at Scale - Workload Identity Pool for GKE Cluster"),
 			Disabled:               pulumi.Bool(false),
 			DisplayName:            pulumi.String(resourceName),
-			WorkloadIdentityPoolId: pulumi.String(fmt.Sprintf("%s-wip-gke-0019", resourceNamePrefix)), // **** TODO: Replace with Pulumi RANDOM ID? ****
-		})
+			WorkloadIdentityPoolId: names.WorkloadIdentityPoolId("wip-gke"),
+		}, pulumi.DependsOn(iamServices))
 		if err != nil {
 			return err
 		}
@@ -269,10 +527,10 @@ func main() {
 		resourceName = fmt.Sprintf("%s-vpc", resourceNamePrefix)
 		gcpNetwork, err := compute.NewNetwork(ctx, resourceName, &compute.NetworkArgs{
 			Project:               pulumi.String(gcpProjectId),
-			Name:                  pulumi.String(resourceName),
+			Name:                  names.ComputeName("vpc"),
 			Description:           pulumi.String("GKE at Scale - Global VPC Network"),
 			AutoCreateSubnetworks: pulumi.Bool(false),
-		}, pulumi.DependsOn(gcpDependencies))
+		}, pulumi.DependsOn(computeServices))
 		if err != nil {
 			return err
 		}
@@ -281,7 +539,7 @@ func main() {
 		resourceName = fmt.Sprintf("%s-fw-in-allow-health-checks", resourceNamePrefix)
 		_, err = compute.NewFirewall(ctx, resourceName, &compute.FirewallArgs{
 			Project:     pulumi.String(gcpProjectId),
-			Name:        pulumi.String(resourceName),
+			Name:        names.ComputeName("fw-in-allow-health-checks"),
 			Description: pulumi.String("GKE at Scale - FW - Allow - Ingress - TCP Health Checks"),
 			Network:     gcpNetwork.Name,
 			Allows: compute.FirewallAllowArray{
@@ -291,6 +549,7 @@ func main() {
 						pulumi.String("80"),
 						pulumi.String("8080"),
 						pulumi.String("443"),
+						pulumi.String(fmt.Sprint(ingressGatewayStatusPort)),
 					},
 				},
 			},
@@ -307,7 +566,7 @@ func main() {
 		resourceName = fmt.Sprintf("%s-fw-in-allow-cluster-app", resourceNamePrefix)
 		_, err = compute.NewFirewall(ctx, resourceName, &compute.FirewallArgs{
 			Project:     pulumi.String(gcpProjectId),
-			Name:        pulumi.String(resourceName),
+			Name:        names.ComputeName("fw-in-allow-cluster-app"),
 			Description: pulumi.String("GKE at Scale - FW - Allow - Ingress - Load Balancer to Application"),
 			Network:     gcpNetwork.Name,
 			Allows: compute.FirewallAllowArray{
@@ -331,185 +590,73 @@ func main() {
 			return err
 		}
 
-		// Create Health Checks (Network Endpoints within Load Balancer)
-		resourceName = fmt.Sprintf("%s-glb-tcp-hc", resourceNamePrefix)
-		gcpGLBTCPHealthCheck, err := compute.NewHealthCheck(ctx, resourceName, &compute.HealthCheckArgs{
-			Project:          pulumi.String(gcpProjectId),
-			CheckIntervalSec: pulumi.Int(1),
-			Description:      pulumi.String("TCP Health Check"),
-			HealthyThreshold: pulumi.Int(4),
-			TcpHealthCheck: &compute.HealthCheckTcpHealthCheckArgs{
-				Port:        pulumi.Int(80),
-				ProxyHeader: pulumi.String("NONE"),
-			},
-			TimeoutSec:         pulumi.Int(1),
-			UnhealthyThreshold: pulumi.Int(5),
-		}, pulumi.DependsOn(gcpDependencies))
-		if err != nil {
-			return err
-		}
-
-		// Create Global Load Balancer Backend Service
-		var backendServiceBackendArray = compute.BackendServiceBackendArray{}
-		resourceName = fmt.Sprintf("%s-glb-bes", resourceNamePrefix)
-		gcpBackendService, err := compute.NewBackendService(ctx, resourceName, &compute.BackendServiceArgs{
-			Project:     pulumi.String(gcpProjectId),
-			Name:        pulumi.String(fmt.Sprintf("%s-bes", resourceNamePrefix)),
-			Description: pulumi.String("GKE At Scale - Global Load Balancer - Backend Service"),
-			CdnPolicy: &compute.BackendServiceCdnPolicyArgs{
-				ClientTtl:  pulumi.Int(5),
-				DefaultTtl: pulumi.Int(5),
-				MaxTtl:     pulumi.Int(5),
-			},
-			ConnectionDrainingTimeoutSec: pulumi.Int(10),
-			Backends:                     backendServiceBackendArray,
-			HealthChecks:                 gcpGLBTCPHealthCheck.ID(),
-		})
-		if err != nil {
-			return err
-		}
-
-		// Create Managed SSL Certificate
-		if SSL {
-			resourceName = fmt.Sprintf("%s-glb-ssl-cert", resourceNamePrefix)
-			gcpGLBManagedSSLCert, err := compute.NewManagedSslCertificate(ctx, resourceName, &compute.ManagedSslCertificateArgs{
+		// Create Firewall Rules - Istio Multi-Cluster Mesh East-West Traffic between Clusters
+		if mesh.Enabled {
+			resourceName = fmt.Sprintf("%s-fw-in-allow-istio-east-west", resourceNamePrefix)
+			_, err = compute.NewFirewall(ctx, resourceName, &compute.FirewallArgs{
 				Project:     pulumi.String(gcpProjectId),
-				Name:        pulumi.String(resourceName),
-				Description: pulumi.String("GKE at Scale - Global Load Balancer - Managed SSL Certificate"),
-				Type:        pulumi.String("MANAGED"),
-				Managed: &compute.ManagedSslCertificateManagedArgs{
-					Domains: pulumi.StringArray{
-						pulumi.String(domain),
+				Name:        names.ComputeName("fw-in-allow-istio-east-west"),
+				Description: pulumi.String("GKE at Scale - FW - Allow - Ingress - Istio East-West Gateways"),
+				Network:     gcpNetwork.Name,
+				Allows: compute.FirewallAllowArray{
+					&compute.FirewallAllowArgs{
+						Protocol: pulumi.String("tcp"),
+						Ports: pulumi.StringArray{
+							pulumi.String("15012"),
+							pulumi.String("15017"),
+							pulumi.String("15021"),
+							pulumi.String("15443"),
+						},
 					},
 				},
-			}, pulumi.DependsOn(gcpDependencies))
-			if err != nil {
-				return err
-			}
-
-			// Create URL Map
-			resourceName = fmt.Sprintf("%s-glb-url-map-https-domain", resourceNamePrefix)
-			gcpGLBURLMapHTTPS, err := compute.NewURLMap(ctx, resourceName, &compute.URLMapArgs{
-				Project:        pulumi.String(gcpProjectId),
-				Name:           pulumi.String(fmt.Sprintf("%s-glb-urlmap-https", resourceNamePrefix)),
-				Description:    pulumi.String("GKE At Scale - Global Load Balancer - HTTPS URL Map"),
-				DefaultService: gcpBackendService.SelfLink,
+				SourceRanges: pulumi.ToStringArray(ipCapacity.clusterSourceRanges(CloudRegions)),
 			})
 			if err != nil {
 				return err
 			}
+		}
 
-			// Create Target HTTPS Proxy
-			resourceName = fmt.Sprintf("%s-glb-https-proxy", resourceNamePrefix)
-			gcpGLBTargetHTTPSProxy, err := compute.NewTargetHttpsProxy(ctx, resourceName, &compute.TargetHttpsProxyArgs{
-				Project: pulumi.String(gcpProjectId),
-				Name:    pulumi.String(resourceName),
-				UrlMap:  gcpGLBURLMapHTTPS.SelfLink,
-				SslCertificates: pulumi.StringArray{
-					gcpGLBManagedSSLCert.SelfLink,
-				},
-			})
+		// Enable the Cloud Service Mesh on the Fleet (Managed Control Plane)
+		var gcpFleetMeshFeature *gkehub.Feature
+		if fleet.ManagedMesh {
+			fleetServices, err := gcpServices.dependencies(fleet.services()...)
 			if err != nil {
 				return err
 			}
-
-			// Global Load Balancer Forwarding Rule for HTTPS Traffic.
-			resourceName = fmt.Sprintf("%s-glb-https-fwd-rule", resourceNamePrefix)
-			_, err = compute.NewGlobalForwardingRule(ctx, resourceName, &compute.GlobalForwardingRuleArgs{
-				Project:             pulumi.String(gcpProjectId),
-				Target:              gcpGLBTargetHTTPSProxy.SelfLink,
-				IpAddress:           gcpGlobalAddress.SelfLink,
-				PortRange:           pulumi.String("443"),
-				LoadBalancingScheme: pulumi.String("EXTERNAL"),
-			})
+			gcpFleetMeshFeature, err = createFleetMeshFeature(ctx, resourceNamePrefix, gcpProjectId, pulumi.DependsOn(fleetServices))
 			if err != nil {
 				return err
 			}
-
 		}
 
-		// Create URL Maps
-		gcpGLBURLMapHTTP := &compute.URLMap{}
-		if domain == "" {
-			// Create URL Map - When No Domain is provided - HTTP Traffic.
-			resourceName = fmt.Sprintf("%s-glb-url-map-http-no-domain", resourceNamePrefix)
-			gcpGLBURLMapHTTP, err = compute.NewURLMap(ctx, resourceName, &compute.URLMapArgs{
-				Project:        pulumi.String(gcpProjectId),
-				Name:           pulumi.String(fmt.Sprintf("%s-glb-urlmap-http", resourceNamePrefix)),
-				Description:    pulumi.String("GKE At Scale - Global Load Balancer - HTTP URL Map"),
-				DefaultService: gcpBackendService.SelfLink,
+		// Create the Global Load Balancer (AutoNeg Ingress Mode)
+		var gcpBackendService *compute.BackendService
+		if ingressMode == ingressModeAutoneg {
+			gcpBackendService, err = createGlobalLoadBalancer(ctx, &globalLoadBalancerArgs{
+				ProjectId:          gcpProjectId,
+				ResourceNamePrefix: resourceNamePrefix,
+				Names:              names,
+				Domain:             domain,
+				SSL:                SSL,
+				BackendProtocol:    backendProtocol,
+				Address:            gcpGlobalAddress,
+				AddressIPv6:        gcpGlobalAddressIPv6,
+				Applications:       applications,
+				Dependencies:       computeServices,
 			})
 			if err != nil {
 				return err
 			}
-
-		} else {
-			// Create URL Map - When Domain is provided - HTTP Traffic.
-			resourceName = fmt.Sprintf("%s-glb-url-map-http-domain", resourceNamePrefix)
-			gcpGLBURLMapHTTP, err = compute.NewURLMap(ctx, resourceName, &compute.URLMapArgs{
-				Project:     pulumi.String(gcpProjectId),
-				Name:        pulumi.String(fmt.Sprintf("%s-glb-urlmap-http", resourceNamePrefix)),
-				Description: pulumi.String("GKE At Scale - Global Load Balancer - HTTP URL Map"),
-				HostRules: &compute.URLMapHostRuleArray{
-					&compute.URLMapHostRuleArgs{
-						Hosts: pulumi.StringArray{
-							pulumi.String(domain),
-						},
-						PathMatcher: pulumi.String("all-paths"),
-						Description: pulumi.String("Default Route All Paths"),
-					},
-				},
-				PathMatchers: &compute.URLMapPathMatcherArray{
-					&compute.URLMapPathMatcherArgs{
-						Name:           pulumi.String("all-paths"),
-						DefaultService: gcpBackendService.SelfLink,
-						PathRules: &compute.URLMapPathMatcherPathRuleArray{
-							&compute.URLMapPathMatcherPathRuleArgs{
-								Paths: pulumi.StringArray{
-									pulumi.String("/*"),
-								},
-								UrlRedirect: &compute.URLMapPathMatcherPathRuleUrlRedirectArgs{
-									StripQuery: pulumi.Bool(false),
-									// If Domain Configured and SSL Enabled
-									HttpsRedirect: pulumi.Bool(SSL),
-								},
-							},
-						},
-					},
-				},
-				DefaultService: gcpBackendService.SelfLink,
-			})
-			if err != nil {
-				return err
-			}
-		}
-
-		// Create Target HTTP Proxy
-		resourceName = fmt.Sprintf("%s-glb-http-proxy", resourceNamePrefix)
-		gcpGLBTargetHTTPProxy, err := compute.NewTargetHttpProxy(ctx, resourceName, &compute.TargetHttpProxyArgs{
-			Project: pulumi.String(gcpProjectId),
-			Name:    pulumi.String(resourceName),
-			UrlMap:  gcpGLBURLMapHTTP.SelfLink,
-		})
-		if err != nil {
-			return err
-		}
-
-		// Create HTTP Global Forwarding Rule
-		resourceName = fmt.Sprintf("%s-glb-http-fwd-rule", resourceNamePrefix)
-		_, err = compute.NewGlobalForwardingRule(ctx, resourceName, &compute.GlobalForwardingRuleArgs{
-			Project:             pulumi.String(gcpProjectId),
-			Target:              gcpGLBTargetHTTPProxy.SelfLink,
-			IpAddress:           gcpGlobalAddress.SelfLink,
-			PortRange:           pulumi.String("80"),
-			LoadBalancingScheme: pulumi.String("EXTERNAL"),
-		})
-		if err != nil {
-			return err
+			// Export the Backend Service Name; Used by the Rollout command to check the NEG Endpoint health of each region
+			ctx.Export("backendService", gcpBackendService.Name)
 		}
 
 		// Process Each Cloud Region;
-		for _, cloudRegion := range CloudRegions {
+		gcpGKEClusters := []pulumi.Resource{}
+		meshClusters := []meshCluster{}
+		multiClusterGatewayClusters := []multiClusterGatewayCluster{}
+		rolloutRegions := pulumi.Array{}
+		for regionIndex, cloudRegion := range CloudRegions {
 			if !cloudRegion.Enabled {
 				// Logging Region Skipping
 				fmt.Printf("[ INFORMATION ] - Cloud Region: %s - SKIPPING\n", cloudRegion.Region)
@@ -519,26 +666,46 @@ func main() {
 			// Logging Region Processing
 			fmt.Printf("[ INFORMATION ] - Cloud Region: %s - PROCESSING\n", cloudRegion.Region)
 
-			// Create VPC Subnet for Cloud Region
+			// Create VPC Subnet for Cloud Region; Nodes use the primary range, Pods & Services the named secondary ranges
 			resourceName := fmt.Sprintf("%s-vpc-subnet-%s", resourceNamePrefix, cloudRegion.Region)
 			gcpSubnetwork, err := compute.NewSubnetwork(ctx, resourceName, &compute.SubnetworkArgs{
 				Project:               pulumi.String(gcpProjectId),
-				Name:                  pulumi.String(resourceName),
+				Name:                  names.ComputeName(fmt.Sprintf("vpc-subnet-%s", cloudRegion.Region)),
 				Description:           pulumi.String(fmt.Sprintf("GKE at Scale - VPC Subnet - %s", cloudRegion.Region)),
 				IpCidrRange:           pulumi.String(cloudRegion.SubnetIp),
 				Region:                pulumi.String(cloudRegion.Region),
 				Network:               gcpNetwork.ID(),
 				PrivateIpGoogleAccess: pulumi.Bool(true),
+				SecondaryIpRanges: compute.SubnetworkSecondaryIpRangeArray{
+					&compute.SubnetworkSecondaryIpRangeArgs{
+						RangeName:   pulumi.String(podsSecondaryRangeName),
+						IpCidrRange: pulumi.String(ipCapacity.podsRange(regionIndex)),
+					},
+					&compute.SubnetworkSecondaryIpRangeArgs{
+						RangeName:   pulumi.String(servicesSecondaryRangeName),
+						IpCidrRange: pulumi.String(ipCapacity.servicesRange(regionIndex)),
+					},
+				},
 			})
 			if err != nil {
 				return err
 			}
 
-			// Create GKE Cluster for Cloud Region
-			cloudRegion.GKEClusterName = fmt.Sprintf("%s-gke-%s", resourceNamePrefix, cloudRegion.Region)
-			gcpGKECluster, err := container.NewCluster(ctx, cloudRegion.GKEClusterName, &container.ClusterArgs{
+			// Create GKE Cluster for Cloud Region; The Multi-Cluster Gateway requires the Gateway API
+			var gcpGKEClusterGatewayApiConfig container.ClusterGatewayApiConfigPtrInput
+			gcpGKEClusterOpts := []pulumi.ResourceOption{pulumi.DependsOn(gkeServices)}
+			if ingressMode == ingressModeMultiClusterGateway {
+				gcpGKEClusterGatewayApiConfig = &container.ClusterGatewayApiConfigArgs{
+					Channel: pulumi.String("CHANNEL_STANDARD"),
+				}
+			} else {
+				gcpGKEClusterOpts = append(gcpGKEClusterOpts, pulumi.IgnoreChanges([]string{"gatewayApiConfig"}))
+			}
+			resourceName = fmt.Sprintf("%s-gke-%s", resourceNamePrefix, cloudRegion.Region)
+			cloudRegion.GKEClusterName = names.ClusterName(fmt.Sprintf("gke-%s", cloudRegion.Region))
+			gcpGKECluster, err := container.NewCluster(ctx, resourceName, &container.ClusterArgs{
 				Project:               pulumi.String(gcpProjectId),
-				Name:                  pulumi.String(cloudRegion.GKEClusterName),
+				Name:                  cloudRegion.GKEClusterName,
 				Network:               gcpNetwork.ID(),
 				Subnetwork:            gcpSubnetwork.ID(),
 				Location:              pulumi.String(cloudRegion.Region),
@@ -547,7 +714,12 @@ func main() {
 				VerticalPodAutoscaling: &container.ClusterVerticalPodAutoscalingArgs{
 					Enabled: pulumi.Bool(true),
 				},
-				IpAllocationPolicy: &container.ClusterIpAllocationPolicyArgs{},
+				IpAllocationPolicy: &container.ClusterIpAllocationPolicyArgs{
+					ClusterSecondaryRangeName:  pulumi.String(podsSecondaryRangeName),
+					ServicesSecondaryRangeName: pulumi.String(servicesSecondaryRangeName),
+				},
+				DefaultMaxPodsPerNode: pulumi.Int(ipCapacity.MaxPodsPerNode),
+				DatapathProvider:      datapath.provider(),
 				MasterAuthorizedNetworksConfig: &container.ClusterMasterAuthorizedNetworksConfigArgs{
 					CidrBlocks: &container.ClusterMasterAuthorizedNetworksConfigCidrBlockArray{
 						&container.ClusterMasterAuthorizedNetworksConfigCidrBlockArgs{
@@ -556,40 +728,41 @@ func main() {
 						},
 					},
 				},
+				GatewayApiConfig: gcpGKEClusterGatewayApiConfig,
 				WorkloadIdentityConfig: &container.ClusterWorkloadIdentityConfigArgs{
 					WorkloadPool: pulumi.String(fmt.Sprintf("%s.svc.id.goog", gcpProjectId)),
 				},
-			}, pulumi.IgnoreChanges([]string{"gatewayApiConfig"}))
+			}, gcpGKEClusterOpts...)
 			if err != nil {
 				return err
 			}
+			gcpGKEClusters = append(gcpGKEClusters, gcpGKECluster)
 
 			// Create GKE Node Pool
 			resourceName = fmt.Sprintf("%s-gke-%s-np-01", resourceNamePrefix, cloudRegion.Region)
 			gcpGKENodePool, err := container.NewNodePool(ctx, resourceName, &container.NodePoolArgs{
 				Cluster:   gcpGKECluster.ID(),
-				Name:      pulumi.String(resourceName),
+				Name:      names.NodePoolName(fmt.Sprintf("gke-%s-np-01", cloudRegion.Region)),
 				NodeCount: pulumi.Int(1),
 				NodeConfig: &container.NodePoolNodeConfigArgs{
 					Preemptible:    pulumi.Bool(false),
 					MachineType:    pulumi.String("e2-medium"),
 					ServiceAccount: gcpServiceAccount.Email,
-					OauthScopes: pulumi.StringArray{
-						pulumi.String("https://www.googleapis.com/auth/cloud-platform"),
-					},
+					OauthScopes:    pulumi.ToStringArray(GKENodeOauthScopes),
 				},
 				Autoscaling: &container.NodePoolAutoscalingArgs{
 					LocationPolicy: pulumi.String("BALANCED"),
-					MaxNodeCount:   pulumi.Int(5),
-					MinNodeCount:   pulumi.Int(1),
+					MaxNodeCount:   pulumi.Int(nodePoolMaxNodeCount),
+					MinNodeCount:   pulumi.Int(nodePoolMinNodeCount),
 				},
-			})
+			}, pulumi.DependsOn(gcpServiceAccountRoles))
 			if err != nil {
 				return err
 			}
 
-			// Create New Kubernetes Provider for Each Cloud Region
-			resourceName = fmt.Sprintf("%s-kubeconfig", cloudRegion.GKEClusterName)
+			// Create New Kubernetes Provider for Each Cloud Region; The name matches the '<cluster>-kubeconfig' name used before
+			// the Google Cloud names included the stack & suffix, so existing providers keep their URN
+			resourceName = fmt.Sprintf("%s-gke-%s-kubeconfig", resourceNamePrefix, cloudRegion.Region)
 			k8sProvider, err := kubernetes.NewProvider(ctx, resourceName, &kubernetes.ProviderArgs{
 				Kubeconfig: generateKubeconfig(gcpGKECluster.Endpoint, gcpGKECluster.Name, gcpGKECluster.MasterAuth),
 			}, pulumi.DependsOn([]pulumi.Resource{gcpGKENodePool}))
@@ -597,154 +770,443 @@ func main() {
 				return err
 			}
 
-			// Install Istio Service Mesh Base
-			resourceName = fmt.Sprintf("%s-istio-base-%s", resourceNamePrefix, cloudRegion.Region)
-			helmIstioBase, err := helm.NewRelease(ctx, resourceName, &helm.ReleaseArgs{
-				Description: pulumi.String("Istio Service Mesh - Install IstioBase"),
-				RepositoryOpts: &helm.RepositoryOptsArgs{
-					Repo: pulumi.String("https://istio-release.storage.googleapis.com/charts"),
-				},
-				Chart:           pulumi.String("base"),
-				Namespace:       pulumi.String("istio-system"),
-				CleanupOnFail:   pulumi.Bool(true),
-				CreateNamespace: pulumi.Bool(true),
-				Values: pulumi.Map{
-					"defaultRevision": pulumi.String("default"),
-				},
-			}, pulumi.Provider(k8sProvider))
-			if err != nil {
-				return err
+			// Configure Network Policy Logging (Dataplane V2)
+			if datapath.NetworkPolicyLogging {
+				err = createNetworkPolicyLogging(ctx, resourceNamePrefix, cloudRegion.Region, pulumi.Provider(k8sProvider), pulumi.Parent(gcpGKENodePool))
+				if err != nil {
+					return err
+				}
 			}
 
-			// Install Istio Service Mesh Istiod
-			resourceName = fmt.Sprintf("%s-istio-istiod-%s", resourceNamePrefix, cloudRegion.Region)
-			helmIstioD, err := helm.NewRelease(ctx, resourceName, &helm.ReleaseArgs{
-				Description: pulumi.String("Istio Service Mesh - Install Istiod"),
-				RepositoryOpts: &helm.RepositoryOptsArgs{
-					Repo: pulumi.String("https://istio-release.storage.googleapis.com/charts"),
-				},
-				Chart:           pulumi.String("istiod"),
-				Namespace:       pulumi.String("istio-system"),
-				CleanupOnFail:   pulumi.Bool(true),
-				CreateNamespace: pulumi.Bool(true),
-			}, pulumi.Provider(k8sProvider), pulumi.DependsOn([]pulumi.Resource{helmIstioBase}), pulumi.Parent(gcpGKENodePool))
-			if err != nil {
-				return err
+			// Register the Cluster to the Fleet
+			var gcpFleetMembership *gkehub.Membership
+			if fleet.Enabled {
+				fleetServices, err := gcpServices.dependencies(FleetServices...)
+				if err != nil {
+					return err
+				}
+				gcpFleetMembership, err = createFleetMembership(ctx, resourceNamePrefix, cloudRegion.Region, gcpProjectId, gcpGKECluster, cloudRegion.GKEClusterName, pulumi.DependsOn(fleetServices))
+				if err != nil {
+					return err
+				}
+			}
+
+			// Resolve the Istio Revisions for this Cloud Region
+			istioRevisions := istio.regionRevisions(cloudRegion.Region)
+
+			// Install the Istio Control Plane; Managed by the Fleet or installed into the Cluster with Helm
+			helmIstioDs := []pulumi.Resource{}
+			var gcpFleetMeshMembership *gkehub.FeatureMembership
+			if fleet.ManagedMesh {
+				fmt.Printf("[ INFORMATION ] - Cloud Region: %s - Istio Control Plane: %s\n", cloudRegion.Region, istioControlPlaneManaged)
+				gcpFleetMeshMembership, err = createFleetMeshMembership(ctx, resourceNamePrefix, cloudRegion.Region, gcpProjectId, gcpFleetMeshFeature, gcpFleetMembership, pulumi.DependsOn([]pulumi.Resource{gcpGKENodePool}))
+				if err != nil {
+					return err
+				}
+				helmIstioDs = append(helmIstioDs, gcpFleetMeshMembership)
+			} else {
+				fmt.Printf("[ INFORMATION ] - Cloud Region: %s - Istio Revision: %s\n", cloudRegion.Region, istioRevisions.Active.Name)
+
+				// Install Istio Service Mesh Base
+				resourceName = fmt.Sprintf("%s-istio-base-%s", resourceNamePrefix, cloudRegion.Region)
+				helmIstioBase, err := helm.NewRelease(ctx, resourceName, &helm.ReleaseArgs{
+					Description: pulumi.String("Istio Service Mesh - Install IstioBase"),
+					RepositoryOpts: &helm.RepositoryOptsArgs{
+						Repo: pulumi.String(istioChartRepo),
+					},
+					Chart:           pulumi.String("base"),
+					Version:         pulumi.String(istioRevisions.latest().Version),
+					Namespace:       pulumi.String("istio-system"),
+					CleanupOnFail:   pulumi.Bool(true),
+					CreateNamespace: pulumi.Bool(true),
+					Values: pulumi.Map{
+						"defaultRevision": pulumi.String(istioRevisions.Active.Name),
+					},
+				}, pulumi.Provider(k8sProvider))
+				if err != nil {
+					return err
+				}
+
+				// Install Istio Multi-Cluster Mesh Intermediate CA for this Cluster
+				istiodDependencies := []pulumi.Resource{helmIstioBase}
+				if mesh.Enabled {
+					k8sMeshCACerts, err := createMeshCACerts(ctx, resourceNamePrefix, cloudRegion.Region, mesh, pulumi.Provider(k8sProvider), pulumi.DependsOn([]pulumi.Resource{helmIstioBase}))
+					if err != nil {
+						return err
+					}
+					istiodDependencies = append(istiodDependencies, k8sMeshCACerts)
+				}
+
+				// Install the Istio CNI Plugin; Sets up the sidecar traffic redirection so pods need no privileged init container
+				// to pass the Pod Security Admission level of their namespace
+				istioCNI := namespaceBaseline.requiresIstioCNI()
+				if istioCNI {
+					resourceName = fmt.Sprintf("%s-istio-cni-%s", resourceNamePrefix, cloudRegion.Region)
+					helmIstioCNI, err := helm.NewRelease(ctx, resourceName, &helm.ReleaseArgs{
+						Name:        pulumi.String("istio-cni"),
+						Description: pulumi.String("Istio Service Mesh - Install Istio CNI Plugin"),
+						RepositoryOpts: &helm.RepositoryOptsArgs{
+							Repo: pulumi.String(istioChartRepo),
+						},
+						Chart:         pulumi.String("cni"),
+						Version:       pulumi.String(istioRevisions.latest().Version),
+						Namespace:     pulumi.String("kube-system"),
+						CleanupOnFail: pulumi.Bool(true),
+						Values: pulumi.Map{
+							"cni": pulumi.Map{
+								// GKE Node CNI Binary Directory
+								"cniBinDir": pulumi.String("/home/kubernetes/bin"),
+							},
+						},
+					}, pulumi.Provider(k8sProvider), pulumi.DependsOn([]pulumi.Resource{helmIstioBase}), pulumi.Parent(gcpGKENodePool))
+					if err != nil {
+						return err
+					}
+					istiodDependencies = append(istiodDependencies, helmIstioCNI)
+					helmIstioDs = append(helmIstioDs, helmIstioCNI)
+				}
+
+				// Install Istio Service Mesh Istiod; One Release per installed Revision
+				helmIstioDs = append(helmIstioDs, helmIstioBase)
+				for _, istioRevision := range istioRevisions.Installed {
+					resourceName = fmt.Sprintf("%s-istio-istiod-%s-%s", resourceNamePrefix, cloudRegion.Region, istioRevision.Name)
+					helmIstioD, err := helm.NewRelease(ctx, resourceName, &helm.ReleaseArgs{
+						Name:        pulumi.String(fmt.Sprintf("istiod-%s", istioRevision.Name)),
+						Description: pulumi.String(fmt.Sprintf("Istio Service Mesh - Install Istiod - Revision %s", istioRevision.Name)),
+						RepositoryOpts: &helm.RepositoryOptsArgs{
+							Repo: pulumi.String(istioChartRepo),
+						},
+						Chart:           pulumi.String("istiod"),
+						Version:         pulumi.String(istioRevision.Version),
+						Namespace:       pulumi.String("istio-system"),
+						CleanupOnFail:   pulumi.Bool(true),
+						CreateNamespace: pulumi.Bool(true),
+						Values: pulumi.Map{
+							"revision": pulumi.String(istioRevision.Name),
+							"global":   mesh.istiodGlobalValues(cloudRegion.GKEClusterName, cloudRegion.Region),
+							"istio_cni": pulumi.Map{
+								"enabled": pulumi.Bool(istioCNI),
+							},
+						},
+					}, pulumi.Provider(k8sProvider), pulumi.DependsOn(istiodDependencies), pulumi.Parent(gcpGKENodePool))
+					if err != nil {
+						return err
+					}
+					helmIstioDs = append(helmIstioDs, helmIstioD)
+				}
+			}
+
+			// Join the Cluster to the Istio Multi-Cluster Mesh
+			if mesh.Enabled {
+				err = createMeshEastWestGateway(ctx, resourceNamePrefix, cloudRegion.Region, mesh, istioRevisions.Active, pulumi.Provider(k8sProvider), pulumi.DependsOn(helmIstioDs), pulumi.Parent(gcpGKENodePool))
+				if err != nil {
+					return err
+				}
+				meshReaderToken, err := createMeshReaderToken(ctx, resourceNamePrefix, cloudRegion.Region, pulumi.Provider(k8sProvider), pulumi.DependsOn(helmIstioDs))
+				if err != nil {
+					return err
+				}
+				meshClusters = append(meshClusters, meshCluster{
+					Region:      cloudRegion.Region,
+					ClusterName: cloudRegion.GKEClusterName,
+					Endpoint:    gcpGKECluster.Endpoint,
+					CaCert:      gcpGKECluster.MasterAuth.ClusterCaCertificate().Elem(),
+					Token:       meshReaderToken,
+					Provider:    k8sProvider,
+				})
+			}
+
+			// Label Namespaces with the Active Istio Revision for Sidecar Injection; The managed control plane injects by the default label
+			k8sNamespaceLabels := pulumi.StringMap{
+				"istio.io/rev": pulumi.String(istioRevisions.Active.Name),
+			}
+			if fleet.ManagedMesh {
+				k8sNamespaceLabels = pulumi.StringMap{
+					"istio-injection": pulumi.String("enabled"),
+				}
 			}
 
-			// Create New Namespace in the GKE Clusters for Application Deployments
+			// Add the Pod Security Admission labels of each Namespace's Baseline
+			k8sNamespaceLabelsFor := func(namespace string) pulumi.StringMap {
+				labels := pulumi.StringMap{}
+				for key, value := range k8sNamespaceLabels {
+					labels[key] = value
+				}
+				for key, value := range namespaceBaseline.forNamespace(namespace).podSecurityLabels() {
+					labels[key] = pulumi.String(value)
+				}
+				return labels
+			}
+
+			// Create New Namespace in the GKE Clusters for the Istio Ingress Gateway & Application Deployments
 			resourceName = fmt.Sprintf("%s-k8s-ns-app-%s", resourceNamePrefix, cloudRegion.Region)
 			k8sAppNamespace, err := k8s.NewNamespace(ctx, resourceName, &k8s.NamespaceArgs{
 				Metadata: &metav1.ObjectMetaArgs{
-					Name: pulumi.String("app-team"),
-					Labels: pulumi.StringMap{
-						"istio-injection": pulumi.String("enabled"),
-					},
+					Name:   pulumi.String(ingressGatewayNamespace),
+					Labels: k8sNamespaceLabelsFor(ingressGatewayNamespace),
 				},
-			}, pulumi.Provider(k8sProvider), pulumi.DependsOn([]pulumi.Resource{helmIstioD}))
+			}, pulumi.Provider(k8sProvider), pulumi.DependsOn(helmIstioDs))
 			if err != nil {
 				return err
 			}
 
-			// Deploy Istio Ingress Gateway into the GKE Clusters
-			resourceName = fmt.Sprintf("%s-istio-igw-%s", resourceNamePrefix, cloudRegion.Region)
-			_, err = helm.NewRelease(ctx, resourceName, &helm.ReleaseArgs{
-				Name:        pulumi.String("istio-ingressgateway"),
-				Description: pulumi.String("Istio Service Mesh - Install Ingress Gateway"),
-				RepositoryOpts: &helm.RepositoryOptsArgs{
-					Repo: pulumi.String("https://istio-release.storage.googleapis.com/charts"),
-				},
-				Chart:         pulumi.String("gateway"),
-				Namespace:     k8sAppNamespace.Metadata.Name(),
-				CleanupOnFail: pulumi.Bool(true),
-				Values: pulumi.Map{
-					"service": pulumi.Map{
-						//"type": pulumi.String("LoadBalancer"),
-						"type": pulumi.String("ClusterIP"),
-						"annotations": pulumi.Map{
-							"cloud.google.com/neg":                 pulumi.String("{\"exposed_ports\": {\"80\":{}}}"),
-							"controller.autoneg.dev/neg":           pulumi.Sprintf("{\"backend_services\":{\"80\":[{\"name\":\"%s\",\"max_rate_per_endpoint\":100}]}}", gcpBackendService.Name),
-							"networking.gke.io/load-balancer-type": pulumi.String("Internal"),
-						},
+			// Create the Namespaces of the Applications deployed to this Cloud Region; The Multi-Cluster Gateway
+			// needs every Application namespace in every Cluster to import the Services & attach the HTTPRoutes
+			k8sNamespaces := map[string]*k8s.Namespace{
+				ingressGatewayNamespace: k8sAppNamespace,
+			}
+			for _, app := range applications {
+				if !app.deployedTo(cloudRegion.Region) && ingressMode != ingressModeMultiClusterGateway {
+					continue
+				}
+				_, err = getOrCreateNamespace(ctx, resourceNamePrefix, cloudRegion.Region, app.Namespace, k8sNamespaces, k8sNamespaceLabelsFor(app.Namespace), pulumi.Provider(k8sProvider), pulumi.DependsOn(helmIstioDs))
+				if err != nil {
+					return err
+				}
+			}
+
+			// Create the Istio Ingress Gateway TLS Certificate (Encrypts Traffic from the Global Load Balancer)
+			if backendTLS {
+				resourceName = fmt.Sprintf("%s-k8s-secret-gateway-tls-%s", resourceNamePrefix, cloudRegion.Region)
+				_, err = k8s.NewSecret(ctx, resourceName, &k8s.SecretArgs{
+					Metadata: &metav1.ObjectMetaArgs{
+						Name:      pulumi.String(ingressGatewayTLSSecretName),
+						Namespace: k8sAppNamespace.Metadata.Name(),
 					},
-				},
-			}, pulumi.Provider(k8sProvider), pulumi.DependsOn([]pulumi.Resource{helmIstioBase, helmIstioD}), pulumi.Parent(gcpGKENodePool))
+					Type: pulumi.String("kubernetes.io/tls"),
+					StringData: pulumi.StringMap{
+						"tls.crt": cfg.RequireSecret("backendTlsCert"),
+						"tls.key": cfg.RequireSecret("backendTlsKey"),
+					},
+				}, pulumi.Provider(k8sProvider))
+				if err != nil {
+					return err
+				}
+			}
+
+			// Create Kubernetes Service Accounts for Application Workload Identities
+			err = createAppIdentityKubernetesServiceAccounts(ctx, resourceNamePrefix, cloudRegion.Region, appIdentities, k8sNamespaces, k8sNamespaceLabelsFor, pulumi.Provider(k8sProvider), pulumi.DependsOn(helmIstioDs))
+			if err != nil {
+				return err
+			}
+
+			// Apply the Istio Mesh Security Baseline (mTLS & Authorization Policies) to every Application Namespace
+			err = createMeshSecurityPolicies(ctx, resourceNamePrefix, cloudRegion.Region, meshSecurity, ingressGatewayNamespace, k8sNamespaces, pulumi.Provider(k8sProvider), pulumi.DependsOn(helmIstioDs))
+			if err != nil {
+				return err
+			}
+
+			// Apply the Namespace Baselines; NetworkPolicies, ResourceQuota & LimitRange
+			k8sNamespaceBaselines, err := createNamespaceBaselines(ctx, resourceNamePrefix, cloudRegion.Region, namespaceBaseline, namespaceTraffic{
+				LoadBalancerToPods:  ingressMode == ingressModeMultiClusterGateway,
+				EastWestGateways:    mesh.Enabled,
+				ManagedControlPlane: fleet.ManagedMesh,
+				MetadataServer:      len(appIdentities) > 0 || fleet.ManagedMesh,
+			}, ingressGatewayNamespace, k8sNamespaces, pulumi.Provider(k8sProvider), pulumi.DependsOn(namespaceResources(k8sNamespaces)))
 			if err != nil {
 				return err
 			}
 
-			// Deploy Cluster Ops components for GKE AutoNeg
-			resourceName = fmt.Sprintf("%s-cluster-ops-%s", resourceNamePrefix, cloudRegion.Region)
-			helmClusterOps, err := helm.NewChart(ctx, resourceName, helm.ChartArgs{
-				Chart:          pulumi.String("cluster-ops"),
-				ResourcePrefix: cloudRegion.Id,
-				Version:        pulumi.String("0.1.0"),
-				Path:           pulumi.String("../apps/helm"),
-				Values: pulumi.Map{
-					"global": pulumi.Map{
-						"labels": pulumi.Map{
+			// Deploy the Istio Ingress Gateway & AutoNeg Controller (AutoNeg Ingress Mode)
+			if ingressMode == ingressModeAutoneg {
+				// Deploy Istio Ingress Gateway into the GKE Clusters
+				helmIngressGatewayValues := ingressGatewayHelmValues(ingressGateway.forRegion(cloudRegion.Region))
+				if !fleet.ManagedMesh {
+					helmIngressGatewayValues["revision"] = pulumi.String(istioRevisions.Active.Name)
+				}
+				helmIngressGatewayValues["service"] = pulumi.Map{
+					//"type": pulumi.String("LoadBalancer"),
+					"type": pulumi.String("ClusterIP"),
+					"annotations": pulumi.Map{
+						"cloud.google.com/neg":                 pulumi.String(fmt.Sprintf("{\"exposed_ports\": {\"%d\":{}}}", backendPort)),
+						"controller.autoneg.dev/neg":           pulumi.Sprintf("{\"backend_services\":{\"%d\":[{\"name\":\"%s\",\"max_rate_per_endpoint\":100}]}}", backendPort, gcpBackendService.Name),
+						"networking.gke.io/load-balancer-type": pulumi.String("Internal"),
+					},
+				}
+				resourceName = fmt.Sprintf("%s-istio-igw-%s", resourceNamePrefix, cloudRegion.Region)
+				_, err = helm.NewRelease(ctx, resourceName, &helm.ReleaseArgs{
+					Name:        pulumi.String("istio-ingressgateway"),
+					Description: pulumi.String("Istio Service Mesh - Install Ingress Gateway"),
+					RepositoryOpts: &helm.RepositoryOptsArgs{
+						Repo: pulumi.String(istioChartRepo),
+					},
+					Chart:         pulumi.String("gateway"),
+					Version:       pulumi.String(istioRevisions.Active.Version),
+					Namespace:     k8sAppNamespace.Metadata.Name(),
+					CleanupOnFail: pulumi.Bool(true),
+					Values:        helmIngressGatewayValues,
+				}, pulumi.Provider(k8sProvider), pulumi.DependsOn(append(k8sNamespaceBaselines, helmIstioDs...)), pulumi.Parent(gcpGKENodePool))
+				if err != nil {
+					return err
+				}
+
+				// Create the Istio Gateway shared by every Application's VirtualService; Istio rejects the same HTTPS server on several Gateways
+				err = createIngressGateway(ctx, resourceNamePrefix, cloudRegion.Region, k8sAppNamespace.Metadata.Name().Elem(), backendTLS, pulumi.Provider(k8sProvider), pulumi.DependsOn(helmIstioDs))
+				if err != nil {
+					return err
+				}
+
+				// Route every host to the Applications deployed to this Cloud Region; Longest path prefix first
+				err = createIngressRoutes(ctx, resourceNamePrefix, cloudRegion.Region, k8sAppNamespace.Metadata.Name().Elem(), ingressRoutes(applications, cloudRegion.Region), pulumi.Provider(k8sProvider), pulumi.DependsOn(helmIstioDs))
+				if err != nil {
+					return err
+				}
+
+				// Deploy Cluster Ops components for GKE AutoNeg
+				resourceName = fmt.Sprintf("%s-cluster-ops-%s", resourceNamePrefix, cloudRegion.Region)
+				_, err = helm.NewChart(ctx, resourceName, helm.ChartArgs{
+					Chart:          pulumi.String("cluster-ops"),
+					ResourcePrefix: cloudRegion.Id,
+					Version:        pulumi.String("0.1.0"),
+					Path:           pulumi.String("../apps/helm"),
+					Values: pulumi.Map{
+						"global": pulumi.Map{
+							"labels": pulumi.Map{
+								"region": pulumi.String(cloudRegion.Region),
+							},
+						},
+						"app": pulumi.Map{
 							"region": pulumi.String(cloudRegion.Region),
 						},
+						"autoneg": autoneg.helmValues(cloudRegion.Region, gcpServiceAccountAutoNeg.Email),
 					},
-					"app": pulumi.Map{
-						"region": pulumi.String(cloudRegion.Region),
+				}, pulumi.Provider(k8sProvider), pulumi.DependsOn(helmIstioDs), pulumi.Parent(gcpGKENodePool))
+				if err != nil {
+					return err
+				}
+			}
+
+			// Serve the Cluster from the Multi-Cluster Gateway (Multi-Cluster Gateway Ingress Mode)
+			if ingressMode == ingressModeMultiClusterGateway {
+				multiClusterGatewayClusters = append(multiClusterGatewayClusters, multiClusterGatewayCluster{
+					Region:       cloudRegion.Region,
+					Membership:   gcpFleetMembership,
+					Provider:     k8sProvider,
+					Dependencies: append(namespaceResources(k8sNamespaces), gcpGKENodePool),
+				})
+			}
+
+			// Application Values for this Cloud Region; The program's values, then the stack-wide 'appValues', then its region overrides
+			appRegionValues := mergeValues(appDefaultValues, appScalingHelmValues(appScaling.forRegion(cloudRegion.Region)), appTrafficPolicyHelmValues(appTrafficPolicy.forRegion(cloudRegion.Region)), map[string]interface{}{
+				"global": map[string]interface{}{
+					"labels": map[string]interface{}{
+						"region":  cloudRegion.Region,
+						"project": gcpProjectId,
+						"prefix":  resourceNamePrefix,
 					},
-					"autoneg": pulumi.Map{
-						"serviceAccount": pulumi.Map{
-							"annotations": pulumi.Map{
-								"iam.gke.io/gcp-service-account": pulumi.String(fmt.Sprintf("autoneg-system@%s.iam.gserviceaccount.com", gcpProjectId)),
-							}},
+				},
+				"gateway": map[string]interface{}{
+					"name": fmt.Sprintf("%s/%s", ingressGatewayNamespace, ingressGatewayName),
+				},
+				"externalService": map[string]interface{}{
+					"enabled": appExternalService,
+				},
+				"deployment": map[string]interface{}{
+					"env": map[string]interface{}{
+						"location": cloudRegion.Region,
+						"platform": "GKE",
 					},
 				},
-			}, pulumi.Provider(k8sProvider), pulumi.DependsOn([]pulumi.Resource{helmIstioBase, helmIstioD}), pulumi.Parent(gcpGKENodePool))
+			}, appValues.forRegion(cloudRegion.Region))
+
+			// Deploy the Applications in the Catalog targeting this Cloud Region; Each layers its own values on top
+			for _, app := range applications {
+				if !app.deployedTo(cloudRegion.Region) {
+					continue
+				}
+				_, err = app.deploy(ctx, resourceNamePrefix, cloudRegion, appRegionValues, pulumi.Provider(k8sProvider), pulumi.DependsOn(append(append(namespaceResources(k8sNamespaces), k8sNamespaceBaselines...), helmIstioDs...)), pulumi.Parent(gcpGKECluster))
+				if err != nil {
+					return err
+				}
+			}
+
+			// Record the Cluster & Namespaces of this Cloud Region; Used by the Rollout command to check its health.
+			// Resources parented to (or provisioned through) the root resources belong to this Cloud Region's stage,
+			// as do the Cluster's Fleet Memberships.
+			rolloutRoots := pulumi.StringArray{
+				gcpSubnetwork.URN(),
+				gcpGKECluster.URN(),
+				gcpGKENodePool.URN(),
+				k8sProvider.URN(),
+			}
+			if gcpFleetMembership != nil {
+				rolloutRoots = append(rolloutRoots, gcpFleetMembership.URN())
+			}
+			if gcpFleetMeshMembership != nil {
+				rolloutRoots = append(rolloutRoots, gcpFleetMeshMembership.URN())
+			}
+			rolloutRegions = append(rolloutRegions, pulumi.Map{
+				"id":         pulumi.String(cloudRegion.Id),
+				"region":     pulumi.String(cloudRegion.Region),
+				"cluster":    gcpGKECluster.Name,
+				"namespaces": pulumi.ToStringArray(namespaceNames(k8sNamespaces)),
+				"roots":      rolloutRoots,
+			})
+		}
+
+		// Exchange Remote Secrets between every Cluster in the Istio Multi-Cluster Mesh
+		err = createMeshRemoteSecrets(ctx, resourceNamePrefix, meshClusters)
+		if err != nil {
+			return err
+		}
+
+		// Deploy the GKE Multi-Cluster Gateway (Multi-Cluster Gateway Ingress Mode)
+		if ingressMode == ingressModeMultiClusterGateway {
+			multiClusterGatewayServices, err := gcpServices.dependencies(MultiClusterGatewayServices...)
 			if err != nil {
 				return err
 			}
-
-			// Bind Kubernetes AutoNeg Service Account to Workload Identity
-			resourceName = fmt.Sprintf("%s-iam-svc-k8s-%s", resourceNamePrefix, cloudRegion.Region)
-			_, err = serviceaccount.NewIAMBinding(ctx, resourceName, &serviceaccount.IAMBindingArgs{
-				ServiceAccountId: gcpServiceAccountAutoNeg.Name,
-				Role:             pulumi.String("roles/iam.workloadIdentityUser"),
-				Members: pulumi.StringArray{
-					pulumi.String(fmt.Sprintf("serviceAccount:%s.svc.id.goog[autoneg-system/autoneg-controller-manager]", gcpProjectId)),
-				},
-			}, pulumi.Provider(k8sProvider), pulumi.DependsOn([]pulumi.Resource{gcpGKENodePool, helmClusterOps}), pulumi.Parent(gcpGKENodePool))
+			err = createMultiClusterGateway(ctx, &multiClusterGatewayArgs{
+				ProjectId:          gcpProjectId,
+				ResourceNamePrefix: resourceNamePrefix,
+				Names:              names,
+				Domain:             domain,
+				SSL:                SSL,
+				Address:            gcpGlobalAddress,
+				GatewayNamespace:   ingressGatewayNamespace,
+				Applications:       applications,
+				Clusters:           multiClusterGatewayClusters,
+				Dependencies:       multiClusterGatewayServices,
+			})
 			if err != nil {
 				return err
 			}
+		}
 
-			// Deploy Application Team Applications
-			resourceName = fmt.Sprintf("%s-app-%s", resourceNamePrefix, cloudRegion.Region)
-			_, err = helm.NewChart(ctx, resourceName, helm.ChartArgs{
-				Chart:          pulumi.String("app-team"),
-				ResourcePrefix: cloudRegion.Id,
-				Version:        pulumi.String("0.1.0"),
-				Path:           pulumi.String("../apps/helm"),
-				Values: pulumi.Map{
-					"global": pulumi.Map{
-						"labels": pulumi.Map{
-							"region":  pulumi.String(cloudRegion.Region),
-							"project": pulumi.String(gcpProjectId),
-							"prefix":  pulumi.String(resourceNamePrefix),
-						},
-					},
-					"deployment": pulumi.Map{
-						"env": pulumi.Map{
-							"customer":         pulumi.String("Pulumi Developers"),
-							"color_primary":    pulumi.String("#805ac3"),
-							"color_secondary":  pulumi.String("#4d5bd9"),
-							"color_background": pulumi.String("#f7bf2a"),
-							"location":         pulumi.String(cloudRegion.Region),
-							"platform":         pulumi.String("GKE"),
-						},
-					},
-				},
-			}, pulumi.Provider(k8sProvider), pulumi.DependsOn([]pulumi.Resource{helmIstioBase, helmIstioD}), pulumi.Parent(gcpGKECluster))
+		// Bind Kubernetes Service Accounts to Workload Identity (Once; shared by every Cluster in the Workload Pool)
+		gcpWorkloadIdentityMembers := []pulumi.Resource{}
+		if len(gcpGKEClusters) > 0 {
+			// Bind Kubernetes AutoNeg Service Account to Workload Identity
+			if ingressMode == ingressModeAutoneg {
+				resourceName = fmt.Sprintf("%s-iam-member-autoneg-workload-identity", resourceNamePrefix)
+				gcpAutoNegWorkloadIdentity, err := serviceaccount.NewIAMMember(ctx, resourceName, &serviceaccount.IAMMemberArgs{
+					ServiceAccountId: gcpServiceAccountAutoNeg.Name,
+					Role:             pulumi.String("roles/iam.workloadIdentityUser"),
+					Member:           pulumi.String(autoneg.workloadIdentityMember(gcpProjectId)),
+				}, pulumi.DependsOn(gcpGKEClusters), pulumi.DependsOn(iamServices))
+				if err != nil {
+					return err
+				}
+				gcpWorkloadIdentityMembers = append(gcpWorkloadIdentityMembers, gcpAutoNegWorkloadIdentity)
+			}
+
+			// Bind Application Kubernetes Service Accounts to Workload Identity
+			gcpAppWorkloadIdentityMembers, err := bindAppIdentityWorkloadIdentity(ctx, gcpProjectId, resourceNamePrefix, appIdentities, pulumi.DependsOn(gcpGKEClusters), pulumi.DependsOn(iamServices))
 			if err != nil {
 				return err
 			}
+			gcpWorkloadIdentityMembers = append(gcpWorkloadIdentityMembers, gcpAppWorkloadIdentityMembers...)
+		}
+
+		// Export the enabled Cloud Regions in rollout order; The Workload Identity bindings shared by every Cluster
+		// are staged with the first Cloud Region, so its health check gates them before the other regions.
+		if len(rolloutRegions) > 0 {
+			firstRegion := rolloutRegions[0].(pulumi.Map)
+			for _, member := range gcpWorkloadIdentityMembers {
+				firstRegion["roots"] = append(firstRegion["roots"].(pulumi.StringArray), member.URN())
+			}
 		}
+		ctx.Export("cloudRegions", rolloutRegions)
 
 		return nil
 	})
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes"
+	"github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/apiextensions"
+	k8s "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/core/v1"
+	helm "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/helm/v3"
+	metav1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/meta/v1"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// Intermediate CA for a single cluster, signed by the shared Mesh Root CA.
+type meshRegionCerts struct {
+	CaCert    string `json:"caCert"`
+	CaKey     string `json:"caKey"`
+	CertChain string `json:"certChain"`
+}
+
+// Certificates for the Multi-Cluster Mesh; Read from the 'istioMeshCerts' secret configuration.
+type meshCerts struct {
+	RootCert string                     `json:"rootCert"`
+	Regions  map[string]meshRegionCerts `json:"regions"`
+}
+
+// Istio Multi-Primary, Multi-Network Mesh Configuration
+type meshConfig struct {
+	Enabled bool
+	MeshId  string
+	Certs   meshCerts
+}
+
+// A Cluster joined to the Multi-Cluster Mesh; Used to build the Remote Secrets each other cluster uses to reach it.
+type meshCluster struct {
+	Region      string
+	ClusterName pulumi.StringOutput
+	Endpoint    pulumi.StringOutput
+	CaCert      pulumi.StringOutput
+	Token       pulumi.StringOutput
+	Provider    *kubernetes.Provider
+}
+
+// Function - Read & Validate the Multi-Cluster Mesh configuration
+func loadMeshConfig(cfg *config.Config, resourceNamePrefix string) (*meshConfig, error) {
+	mesh := &meshConfig{
+		Enabled: cfg.GetBool("istioMultiCluster"),
+		MeshId:  cfg.Get("istioMeshId"),
+	}
+	if !mesh.Enabled {
+		return mesh, nil
+	}
+	if mesh.MeshId == "" {
+		mesh.MeshId = fmt.Sprintf("%s-mesh", resourceNamePrefix)
+	}
+	if _, err := cfg.GetSecretObject("istioMeshCerts", &mesh.Certs); err != nil {
+		return nil, fmt.Errorf("[CONFIGURATION] - Istio Mesh Certificates: %w", err)
+	}
+	if mesh.Certs.RootCert == "" {
+		return nil, fmt.Errorf("[CONFIGURATION] - Istio Multi-Cluster: 'istioMeshCerts.rootCert' must be set (as a secret) when the multi-cluster mesh is enabled")
+	}
+	fmt.Printf("[CONFIGURATION] - Istio Multi-Cluster: Enabled; Clusters will join mesh '%s' (Multi-Primary, Multi-Network).\n", mesh.MeshId)
+	return mesh, nil
+}
+
+// Function - The Istio Network name for a region; Each cluster is its own network reached through its east-west gateway
+func (m *meshConfig) network(region string) string {
+	return fmt.Sprintf("network-%s", region)
+}
+
+// Function - The 'global' Helm values that join istiod to the Multi-Cluster Mesh
+func (m *meshConfig) istiodGlobalValues(clusterName pulumi.StringInput, region string) pulumi.Map {
+	if !m.Enabled {
+		return pulumi.Map{}
+	}
+	return pulumi.Map{
+		"meshID": pulumi.String(m.MeshId),
+		"multiCluster": pulumi.Map{
+			"clusterName": clusterName,
+		},
+		"network": pulumi.String(m.network(region)),
+	}
+}
+
+// Function - Create the 'cacerts' secret holding the region's Intermediate CA so istiod signs workload certificates
+// that every other cluster trusts through the shared Root CA.
+func createMeshCACerts(ctx *pulumi.Context, resourceNamePrefix string, region string, mesh *meshConfig, opts ...pulumi.ResourceOption) (*k8s.Secret, error) {
+	certs, ok := mesh.Certs.Regions[region]
+	if !ok || certs.CaCert == "" || certs.CaKey == "" || certs.CertChain == "" {
+		return nil, fmt.Errorf("[CONFIGURATION] - Istio Multi-Cluster: 'istioMeshCerts.regions.%s' must provide caCert, caKey & certChain", region)
+	}
+
+	resourceName := fmt.Sprintf("%s-istio-cacerts-%s", resourceNamePrefix, region)
+	return k8s.NewSecret(ctx, resourceName, &k8s.SecretArgs{
+		Metadata: &metav1.ObjectMetaArgs{
+			Name:      pulumi.String("cacerts"),
+			Namespace: pulumi.String("istio-system"),
+		},
+		StringData: pulumi.ToSecret(pulumi.StringMap{
+			"ca-cert.pem":    pulumi.String(certs.CaCert),
+			"ca-key.pem":     pulumi.String(certs.CaKey),
+			"root-cert.pem":  pulumi.String(mesh.Certs.RootCert),
+			"cert-chain.pem": pulumi.String(certs.CertChain),
+		}).(pulumi.StringMapOutput),
+	}, opts...)
+}
+
+// Function - Deploy the East-West Gateway that exposes a cluster's services (and istiod) to the other networks in the mesh.
+// The gateway is an Internal Load Balancer with global access as all clusters share the same VPC.
+func createMeshEastWestGateway(ctx *pulumi.Context, resourceNamePrefix string, region string, mesh *meshConfig, revision istioRevision, opts ...pulumi.ResourceOption) error {
+	resourceName := fmt.Sprintf("%s-istio-ewgw-%s", resourceNamePrefix, region)
+	helmEastWestGateway, err := helm.NewRelease(ctx, resourceName, &helm.ReleaseArgs{
+		Name:        pulumi.String("istio-eastwestgateway"),
+		Description: pulumi.String("Istio Service Mesh - Install East-West Gateway"),
+		RepositoryOpts: &helm.RepositoryOptsArgs{
+			Repo: pulumi.String(istioChartRepo),
+		},
+		Chart:         pulumi.String("gateway"),
+		Version:       pulumi.String(revision.Version),
+		Namespace:     pulumi.String("istio-system"),
+		CleanupOnFail: pulumi.Bool(true),
+		Values: pulumi.Map{
+			"revision":       pulumi.String(revision.Name),
+			"networkGateway": pulumi.String(mesh.network(region)),
+			"labels": pulumi.Map{
+				"istio": pulumi.String("eastwestgateway"),
+			},
+			"service": pulumi.Map{
+				"type": pulumi.String("LoadBalancer"),
+				"annotations": pulumi.Map{
+					"networking.gke.io/load-balancer-type":                         pulumi.String("Internal"),
+					"networking.gke.io/internal-load-balancer-allow-global-access": pulumi.String("true"),
+				},
+				"ports": pulumi.Array{
+					pulumi.Map{"name": pulumi.String("status-port"), "port": pulumi.Int(15021), "targetPort": pulumi.Int(15021)},
+					pulumi.Map{"name": pulumi.String("tls"), "port": pulumi.Int(15443), "targetPort": pulumi.Int(15443)},
+					pulumi.Map{"name": pulumi.String("tls-istiod"), "port": pulumi.Int(15012), "targetPort": pulumi.Int(15012)},
+					pulumi.Map{"name": pulumi.String("tls-webhook"), "port": pulumi.Int(15017), "targetPort": pulumi.Int(15017)},
+				},
+			},
+		},
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	// Expose all services in the cluster to the other networks through the East-West Gateway (mTLS passthrough)
+	resourceName = fmt.Sprintf("%s-istio-cross-network-gw-%s", resourceNamePrefix, region)
+	_, err = apiextensions.NewCustomResource(ctx, resourceName, &apiextensions.CustomResourceArgs{
+		ApiVersion: pulumi.String("networking.istio.io/v1beta1"),
+		Kind:       pulumi.String("Gateway"),
+		Metadata: &metav1.ObjectMetaArgs{
+			Name:      pulumi.String("cross-network-gateway"),
+			Namespace: pulumi.String("istio-system"),
+		},
+		OtherFields: kubernetes.UntypedArgs{
+			"spec": pulumi.Map{
+				"selector": pulumi.StringMap{
+					"istio": pulumi.String("eastwestgateway"),
+				},
+				"servers": pulumi.Array{
+					pulumi.Map{
+						"port": pulumi.Map{
+							"number":   pulumi.Int(15443),
+							"name":     pulumi.String("tls"),
+							"protocol": pulumi.String("TLS"),
+						},
+						"tls": pulumi.Map{
+							"mode": pulumi.String("AUTO_PASSTHROUGH"),
+						},
+						"hosts": pulumi.StringArray{
+							pulumi.String("*.local"),
+						},
+					},
+				},
+			},
+		},
+	}, append(opts, pulumi.DependsOn([]pulumi.Resource{helmEastWestGateway}))...)
+	return err
+}
+
+// Function - Create a token for the 'istio-reader-service-account' (installed by the Istio base chart);
+// Other clusters in the mesh use it to discover this cluster's services & endpoints.
+func createMeshReaderToken(ctx *pulumi.Context, resourceNamePrefix string, region string, opts ...pulumi.ResourceOption) (pulumi.StringOutput, error) {
+	resourceName := fmt.Sprintf("%s-istio-reader-token-%s", resourceNamePrefix, region)
+	k8sTokenSecret, err := k8s.NewSecret(ctx, resourceName, &k8s.SecretArgs{
+		Metadata: &metav1.ObjectMetaArgs{
+			Name:      pulumi.String("istio-reader-service-account-token"),
+			Namespace: pulumi.String("istio-system"),
+			Annotations: pulumi.StringMap{
+				"kubernetes.io/service-account.name": pulumi.String("istio-reader-service-account"),
+			},
+		},
+		Type: pulumi.String("kubernetes.io/service-account-token"),
+	}, opts...)
+	if err != nil {
+		return pulumi.StringOutput{}, err
+	}
+
+	// Read the Secret back through the regional Kubernetes Provider; The token controller populates its token after it is created
+	resourceName = fmt.Sprintf("%s-istio-reader-token-data-%s", resourceNamePrefix, region)
+	k8sTokenSecretData, err := k8s.GetSecret(ctx, resourceName, k8sTokenSecret.ID(), nil, append(opts, pulumi.DependsOn([]pulumi.Resource{k8sTokenSecret}))...)
+	if err != nil {
+		return pulumi.StringOutput{}, err
+	}
+	return pulumi.ToSecret(k8sTokenSecretData.Data.MapIndex(pulumi.String("token")).ApplyT(func(encoded string) (string, error) {
+		if encoded == "" {
+			return "", fmt.Errorf("secret istio-system/istio-reader-service-account-token: token not populated yet; run the update again")
+		}
+		token, err := base64.StdEncoding.DecodeString(encoded)
+		return string(token), err
+	})).(pulumi.StringOutput), nil
+}
+
+// Function - Exchange Remote Secrets between every pair of clusters in the mesh, so each istiod watches every other cluster.
+func createMeshRemoteSecrets(ctx *pulumi.Context, resourceNamePrefix string, clusters []meshCluster) error {
+	for _, local := range clusters {
+		for _, remote := range clusters {
+			if local.Region == remote.Region {
+				continue
+			}
+			resourceName := fmt.Sprintf("%s-istio-remote-secret-%s-%s", resourceNamePrefix, local.Region, remote.Region)
+			_, err := k8s.NewSecret(ctx, resourceName, &k8s.SecretArgs{
+				Metadata: &metav1.ObjectMetaArgs{
+					Name:      pulumi.Sprintf("istio-remote-secret-%s", remote.ClusterName),
+					Namespace: pulumi.String("istio-system"),
+					Labels: pulumi.StringMap{
+						"istio/multiCluster": pulumi.String("true"),
+					},
+					Annotations: pulumi.StringMap{
+						"networking.istio.io/cluster": remote.ClusterName,
+					},
+				},
+				// The Remote Secret is keyed by the remote Cluster Name
+				StringData: pulumi.ToSecret(pulumi.All(remote.ClusterName, generateRemoteKubeconfig(remote)).ApplyT(func(args []interface{}) map[string]string {
+					return map[string]string{args[0].(string): args[1].(string)}
+				})).(pulumi.StringMapOutput),
+			}, pulumi.Provider(local.Provider))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Function - Generate a token based KubeConfig that istiod uses to reach a remote cluster
+func generateRemoteKubeconfig(remote meshCluster) pulumi.StringOutput {
+	return pulumi.Sprintf(`apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority-data: %s
+    server: https://%s
+  name: %s
+contexts:
+- context:
+    cluster: %s
+    user: %s
+  name: %s
+current-context: %s
+kind: Config
+preferences: {}
+users:
+- name: %s
+  user:
+    token: %s
+`,
+		remote.CaCert, remote.Endpoint, remote.ClusterName, remote.ClusterName, remote.ClusterName, remote.ClusterName, remote.ClusterName, remote.ClusterName, remote.Token)
+}
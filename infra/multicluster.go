@@ -0,0 +1,290 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/compute"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/gkehub"
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/projects"
+	"github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes"
+	"github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/apiextensions"
+	metav1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/meta/v1"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Ingress Modes; How traffic from the internet reaches the applications in the regional clusters.
+const (
+	// A hand-built Global Load Balancer with AutoNeg registering the Istio Ingress Gateway NEGs
+	ingressModeAutoneg = "autoneg"
+	// A Google managed GKE Multi-Cluster Gateway routing directly to the application pods
+	ingressModeMultiClusterGateway = "multicluster-gateway"
+)
+
+// Google API's required by the GKE Multi-Cluster Gateway
+var MultiClusterGatewayServices = []string{
+	"multiclusteringress.googleapis.com",
+	"multiclusterservicediscovery.googleapis.com",
+	"trafficdirector.googleapis.com",
+	"dns.googleapis.com",
+	"cloudresourcemanager.googleapis.com",
+}
+
+// A regional Cluster registered to the Fleet for the Multi-Cluster Gateway
+type multiClusterGatewayCluster struct {
+	Region     string
+	Membership *gkehub.Membership
+	Provider   *kubernetes.Provider
+	// Resources in the Cluster the Gateway & Service Exports must wait for (eg. the Application Namespace)
+	Dependencies []pulumi.Resource
+}
+
+// Multi-Cluster Gateway Configuration
+type multiClusterGatewayArgs struct {
+	ProjectId          string
+	ResourceNamePrefix string
+	Names              *stackNamer
+	Domain             string
+	SSL                bool
+	Address            *compute.GlobalAddress
+	// The Gateway is deployed into the Gateway Namespace; Each Application is exported from the Clusters it is
+	// deployed to and routed to with an HTTPRoute in its own namespace.
+	GatewayNamespace string
+	Applications     []*application
+	Clusters         []multiClusterGatewayCluster
+	Dependencies     []pulumi.Resource
+}
+
+// Function - Enable Multi-Cluster Services & Multi-Cluster Ingress on the Fleet, export the Application Services from
+// their Clusters and deploy the Multi-Cluster Gateway & HTTPRoutes into the Config Cluster (the first Cluster).
+func createMultiClusterGateway(ctx *pulumi.Context, mcg *multiClusterGatewayArgs) error {
+	if len(mcg.Clusters) == 0 {
+		return nil
+	}
+	configCluster := mcg.Clusters[0]
+
+	// Enable Multi-Cluster Services on the Fleet
+	resourceName := fmt.Sprintf("%s-fleet-feature-mcs", mcg.ResourceNamePrefix)
+	gcpFeatureMCS, err := gkehub.NewFeature(ctx, resourceName, &gkehub.FeatureArgs{
+		Project:  pulumi.String(mcg.ProjectId),
+		Name:     pulumi.String("multiclusterservicediscovery"),
+		Location: pulumi.String("global"),
+	}, pulumi.DependsOn(mcg.Dependencies))
+	if err != nil {
+		return err
+	}
+
+	// Enable Multi-Cluster Ingress (Gateway) on the Fleet, managed from the Config Cluster
+	resourceName = fmt.Sprintf("%s-fleet-feature-mci", mcg.ResourceNamePrefix)
+	gcpFeatureMCI, err := gkehub.NewFeature(ctx, resourceName, &gkehub.FeatureArgs{
+		Project:  pulumi.String(mcg.ProjectId),
+		Name:     pulumi.String("multiclusteringress"),
+		Location: pulumi.String("global"),
+		Spec: &gkehub.FeatureSpecArgs{
+			Multiclusteringress: &gkehub.FeatureSpecMulticlusteringressArgs{
+				ConfigMembership: configCluster.Membership.ID(),
+			},
+		},
+	}, pulumi.DependsOn(mcg.Dependencies))
+	if err != nil {
+		return err
+	}
+
+	// Allow the Multi-Cluster Services Importer to read the VPC Network
+	resourceName = fmt.Sprintf("%s-iam-member-mcs-importer", mcg.ResourceNamePrefix)
+	_, err = projects.NewIAMMember(ctx, resourceName, &projects.IAMMemberArgs{
+		Project: pulumi.String(mcg.ProjectId),
+		Role:    pulumi.String("roles/compute.networkViewer"),
+		Member:  pulumi.String(fmt.Sprintf("serviceAccount:%s.svc.id.goog[gke-mcs/gke-mcs-importer]", mcg.ProjectId)),
+	}, pulumi.DependsOn([]pulumi.Resource{gcpFeatureMCS}), pulumi.DependsOn(mcg.Dependencies))
+	if err != nil {
+		return err
+	}
+
+	// Export each Application Service from the Clusters it is deployed to
+	for _, app := range mcg.Applications {
+		for _, cluster := range mcg.Clusters {
+			if !app.deployedTo(cluster.Region) {
+				continue
+			}
+			resourceName = fmt.Sprintf("%s-mcs-export-%s-%s", mcg.ResourceNamePrefix, app.Name, cluster.Region)
+			_, err = apiextensions.NewCustomResource(ctx, resourceName, &apiextensions.CustomResourceArgs{
+				ApiVersion: pulumi.String("net.gke.io/v1"),
+				Kind:       pulumi.String("ServiceExport"),
+				Metadata: &metav1.ObjectMetaArgs{
+					Name:      pulumi.String(app.Service),
+					Namespace: pulumi.String(app.Namespace),
+				},
+			}, pulumi.Provider(cluster.Provider), pulumi.DependsOn(append([]pulumi.Resource{gcpFeatureMCS}, cluster.Dependencies...)))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Gateway Listeners; HTTPS uses the Managed SSL Certificate when a Domain is configured.
+	// HTTPRoutes are attached from every Application namespace.
+	allowedRoutes := pulumi.Map{
+		"namespaces": pulumi.Map{
+			"from": pulumi.String("All"),
+		},
+	}
+	listeners := pulumi.Array{
+		pulumi.Map{
+			"name":          pulumi.String("http"),
+			"protocol":      pulumi.String("HTTP"),
+			"port":          pulumi.Int(80),
+			"allowedRoutes": allowedRoutes,
+		},
+	}
+	if mcg.SSL {
+		resourceName = fmt.Sprintf("%s-mcg-ssl-cert", mcg.ResourceNamePrefix)
+		gcpManagedSSLCert, err := compute.NewManagedSslCertificate(ctx, resourceName, &compute.ManagedSslCertificateArgs{
+			Project:     pulumi.String(mcg.ProjectId),
+			Name:        mcg.Names.ComputeName("mcg-ssl-cert"),
+			Description: pulumi.String("GKE at Scale - Multi-Cluster Gateway - Managed SSL Certificate"),
+			Type:        pulumi.String("MANAGED"),
+			Managed: &compute.ManagedSslCertificateManagedArgs{
+				// The Domain & every Application host
+				Domains: pulumi.ToStringArray(applicationHosts(mcg.Applications, mcg.Domain)),
+			},
+		}, pulumi.DependsOn(mcg.Dependencies))
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, pulumi.Map{
+			"name":          pulumi.String("https"),
+			"protocol":      pulumi.String("HTTPS"),
+			"port":          pulumi.Int(443),
+			"allowedRoutes": allowedRoutes,
+			"tls": pulumi.Map{
+				"mode": pulumi.String("Terminate"),
+				"options": pulumi.StringMap{
+					"networking.gke.io/pre-shared-certs": gcpManagedSSLCert.Name,
+				},
+			},
+		})
+	}
+
+	// Deploy the Multi-Cluster Gateway into the Config Cluster
+	resourceName = fmt.Sprintf("%s-mcg-gateway", mcg.ResourceNamePrefix)
+	k8sGateway, err := apiextensions.NewCustomResource(ctx, resourceName, &apiextensions.CustomResourceArgs{
+		ApiVersion: pulumi.String("gateway.networking.k8s.io/v1beta1"),
+		Kind:       pulumi.String("Gateway"),
+		Metadata: &metav1.ObjectMetaArgs{
+			Name:      pulumi.String("external-http"),
+			Namespace: pulumi.String(mcg.GatewayNamespace),
+		},
+		OtherFields: kubernetes.UntypedArgs{
+			"spec": pulumi.Map{
+				"gatewayClassName": pulumi.String("gke-l7-global-external-managed-mc"),
+				"listeners":        listeners,
+				"addresses": pulumi.Array{
+					pulumi.Map{
+						"type":  pulumi.String("NamedAddress"),
+						"value": mcg.Address.Name,
+					},
+				},
+			},
+		},
+	}, pulumi.Provider(configCluster.Provider), pulumi.DependsOn(append([]pulumi.Resource{gcpFeatureMCI}, configCluster.Dependencies...)))
+	if err != nil {
+		return err
+	}
+
+	// Redirect HTTP to HTTPS; The Application HTTPRoutes then only attach to the https listener
+	appParentRef := pulumi.Map{
+		"kind":      pulumi.String("Gateway"),
+		"name":      pulumi.String("external-http"),
+		"namespace": pulumi.String(mcg.GatewayNamespace),
+	}
+	if mcg.SSL {
+		appParentRef["sectionName"] = pulumi.String("https")
+		resourceName = fmt.Sprintf("%s-mcg-httproute-https-redirect", mcg.ResourceNamePrefix)
+		_, err = apiextensions.NewCustomResource(ctx, resourceName, &apiextensions.CustomResourceArgs{
+			ApiVersion: pulumi.String("gateway.networking.k8s.io/v1beta1"),
+			Kind:       pulumi.String("HTTPRoute"),
+			Metadata: &metav1.ObjectMetaArgs{
+				Name:      pulumi.String("https-redirect"),
+				Namespace: pulumi.String(mcg.GatewayNamespace),
+			},
+			OtherFields: kubernetes.UntypedArgs{
+				"spec": pulumi.Map{
+					"parentRefs": pulumi.Array{
+						pulumi.Map{
+							"kind":        pulumi.String("Gateway"),
+							"name":        pulumi.String("external-http"),
+							"namespace":   pulumi.String(mcg.GatewayNamespace),
+							"sectionName": pulumi.String("http"),
+						},
+					},
+					"hostnames": pulumi.ToStringArray(applicationHosts(mcg.Applications, mcg.Domain)),
+					"rules": pulumi.Array{
+						pulumi.Map{
+							"filters": pulumi.Array{
+								pulumi.Map{
+									"type": pulumi.String("RequestRedirect"),
+									"requestRedirect": pulumi.Map{
+										"scheme":     pulumi.String("https"),
+										"statusCode": pulumi.Int(301),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}, pulumi.Provider(configCluster.Provider), pulumi.DependsOn(append([]pulumi.Resource{k8sGateway}, configCluster.Dependencies...)))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Route each Application's host & path to its Service Import (Backed by the Clusters it is deployed to)
+	for _, app := range mcg.Applications {
+		hostnames := pulumi.StringArray{}
+		if app.Host != "" {
+			hostnames = append(hostnames, pulumi.String(app.Host))
+		} else if mcg.Domain != "" {
+			hostnames = append(hostnames, pulumi.String(mcg.Domain))
+		}
+		resourceName = fmt.Sprintf("%s-mcg-httproute-%s", mcg.ResourceNamePrefix, app.Name)
+		_, err = apiextensions.NewCustomResource(ctx, resourceName, &apiextensions.CustomResourceArgs{
+			ApiVersion: pulumi.String("gateway.networking.k8s.io/v1beta1"),
+			Kind:       pulumi.String("HTTPRoute"),
+			Metadata: &metav1.ObjectMetaArgs{
+				Name:      pulumi.String(app.Name),
+				Namespace: pulumi.String(app.Namespace),
+			},
+			OtherFields: kubernetes.UntypedArgs{
+				"spec": pulumi.Map{
+					"parentRefs": pulumi.Array{appParentRef},
+					"hostnames":  hostnames,
+					"rules": pulumi.Array{
+						pulumi.Map{
+							"matches": pulumi.Array{
+								pulumi.Map{
+									"path": pulumi.Map{
+										"type":  pulumi.String("PathPrefix"),
+										"value": pulumi.String(app.pathPrefix()),
+									},
+								},
+							},
+							"backendRefs": pulumi.Array{
+								pulumi.Map{
+									"group": pulumi.String("net.gke.io"),
+									"kind":  pulumi.String("ServiceImport"),
+									"name":  pulumi.String(app.Service),
+									"port":  pulumi.Int(app.Port),
+								},
+							},
+						},
+					},
+				},
+			},
+		}, pulumi.Provider(configCluster.Provider), pulumi.DependsOn(append([]pulumi.Resource{k8sGateway}, configCluster.Dependencies...)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/pulumi-random/sdk/v4/go/random"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Google Cloud identifier rules for each type of resource we name.
+type nameRule struct {
+	MaxLength int
+	Separator string
+	Invalid   *regexp.Regexp
+}
+
+var (
+	// Service Account IDs: 6-30 characters, lowercase letters, digits & hyphens.
+	nameRuleServiceAccount = nameRule{MaxLength: 30, Separator: "-", Invalid: regexp.MustCompile(`[^a-z0-9-]+`)}
+	// Custom IAM Role IDs: up to 64 characters, letters, digits, underscores & periods.
+	nameRuleCustomRole = nameRule{MaxLength: 64, Separator: "_", Invalid: regexp.MustCompile(`[^a-z0-9_.]+`)}
+	// Workload Identity Pool IDs: 4-32 characters, lowercase letters, digits & hyphens.
+	nameRuleWorkloadIdentityPool = nameRule{MaxLength: 32, Separator: "-", Invalid: regexp.MustCompile(`[^a-z0-9-]+`)}
+	// GKE Cluster Names: up to 40 characters, lowercase letters, digits & hyphens.
+	nameRuleCluster = nameRule{MaxLength: 40, Separator: "-", Invalid: regexp.MustCompile(`[^a-z0-9-]+`)}
+	// GKE Node Pool Names: up to 40 characters, lowercase letters, digits & hyphens.
+	nameRuleNodePool = nameRule{MaxLength: 40, Separator: "-", Invalid: regexp.MustCompile(`[^a-z0-9-]+`)}
+	// Compute Engine Names (RFC1035): up to 63 characters, lowercase letters, digits & hyphens.
+	nameRuleCompute = nameRule{MaxLength: 63, Separator: "-", Invalid: regexp.MustCompile(`[^a-z0-9-]+`)}
+)
+
+var namePrefixPattern = regexp.MustCompile(`^[a-z][a-z0-9]{0,4}$`)
+var nameSuffixPattern = regexp.MustCompile(`^[a-z0-9]{4}$`)
+
+// Builds Google Cloud identifiers from the Prefix, Stack and Suffix so several stacks
+// (and prefixes) can share a single Google Cloud Project without colliding.
+type resourceNamer struct {
+	Prefix string
+	Stack  string
+	Suffix string
+}
+
+// The Google Cloud identifiers of the stack; Outputs of the Suffix, which is generated once & kept in the
+// stack's state (or set with 'nameSuffix') so it never changes between deployments.
+type stackNamer struct {
+	Prefix string
+	Stack  string
+	Suffix pulumi.StringOutput
+}
+
+// Function - Create the Stack Namer; When no Suffix is provided a random one is generated on the first deployment
+func newStackNamer(ctx *pulumi.Context, prefix string, suffix string) (*stackNamer, error) {
+	if !namePrefixPattern.MatchString(prefix) {
+		return nil, fmt.Errorf("[CONFIGURATION] - Prefix: '%s' must start with a lowercase letter and contain only lowercase letters and digits", prefix)
+	}
+	names := &stackNamer{
+		Prefix: prefix,
+		Stack:  strings.ToLower(ctx.Stack()),
+	}
+	if suffix != "" {
+		if !nameSuffixPattern.MatchString(suffix) {
+			return nil, fmt.Errorf("[CONFIGURATION] - Name Suffix: '%s' must be exactly 4 lowercase letters or digits", suffix)
+		}
+		names.Suffix = pulumi.String(suffix).ToStringOutput()
+		return names, nil
+	}
+	resourceName := fmt.Sprintf("%s-name-suffix", prefix)
+	randomSuffix, err := random.NewRandomString(ctx, resourceName, &random.RandomStringArgs{
+		Length:  pulumi.Int(4),
+		Upper:   pulumi.Bool(false),
+		Special: pulumi.Bool(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+	names.Suffix = randomSuffix.Result
+	return names, nil
+}
+
+// Function - Build a name with the given Resource Namer method once the Suffix is known
+func (n *stackNamer) name(build func(*resourceNamer, string) string, name string) pulumi.StringOutput {
+	return n.Suffix.ApplyT(func(suffix string) string {
+		return build(&resourceNamer{Prefix: n.Prefix, Stack: n.Stack, Suffix: suffix}, name)
+	}).(pulumi.StringOutput)
+}
+
+// Service Account ID of the stack
+func (n *stackNamer) ServiceAccountId(name string) pulumi.StringOutput {
+	return n.name((*resourceNamer).ServiceAccountId, name)
+}
+
+// Custom IAM Role ID of the stack
+func (n *stackNamer) CustomRoleId(name string) pulumi.StringOutput {
+	return n.name((*resourceNamer).CustomRoleId, name)
+}
+
+// Workload Identity Pool ID of the stack
+func (n *stackNamer) WorkloadIdentityPoolId(name string) pulumi.StringOutput {
+	return n.name((*resourceNamer).WorkloadIdentityPoolId, name)
+}
+
+// GKE Cluster Name of the stack
+func (n *stackNamer) ClusterName(name string) pulumi.StringOutput {
+	return n.name((*resourceNamer).ClusterName, name)
+}
+
+// GKE Node Pool Name of the stack
+func (n *stackNamer) NodePoolName(name string) pulumi.StringOutput {
+	return n.name((*resourceNamer).NodePoolName, name)
+}
+
+// Compute Engine Resource Name of the stack
+func (n *stackNamer) ComputeName(name string) pulumi.StringOutput {
+	return n.name((*resourceNamer).ComputeName, name)
+}
+
+// Service Account ID, eg. 'gas-svc-gke-nodes-dev-1a2b'
+func (n *resourceNamer) ServiceAccountId(name string) string {
+	return n.build(nameRuleServiceAccount, name)
+}
+
+// Custom IAM Role ID, eg. 'gas_iam_role_autoneg_dev_1a2b'
+func (n *resourceNamer) CustomRoleId(name string) string {
+	return n.build(nameRuleCustomRole, name)
+}
+
+// Workload Identity Pool ID, eg. 'gas-wip-gke-dev-1a2b'
+func (n *resourceNamer) WorkloadIdentityPoolId(name string) string {
+	return n.build(nameRuleWorkloadIdentityPool, name)
+}
+
+// GKE Cluster Name, eg. 'gas-gke-us-central1-dev-1a2b'
+func (n *resourceNamer) ClusterName(name string) string {
+	return n.build(nameRuleCluster, name)
+}
+
+// GKE Node Pool Name, eg. 'gas-gke-us-central1-np-01-dev-1a2b'
+func (n *resourceNamer) NodePoolName(name string) string {
+	return n.build(nameRuleNodePool, name)
+}
+
+// Compute Engine Resource Name, eg. 'gas-vpc-dev-1a2b'
+func (n *resourceNamer) ComputeName(name string) string {
+	return n.build(nameRuleCompute, name)
+}
+
+// Function - Join the Prefix, Name, Stack & Suffix following the given rule. The Stack is
+// shortened (or dropped) first, then the Name, so the Prefix and Suffix are always kept.
+// A shortened Name ends with a hash of the full Name so distinct Names stay distinct.
+func (n *resourceNamer) build(rule nameRule, name string) string {
+	name = sanitizeNamePart(rule, name)
+	stack := sanitizeNamePart(rule, n.Stack)
+
+	fixed := len(n.Prefix) + len(rule.Separator) + len(name) + len(rule.Separator) + len(n.Suffix)
+	if available := rule.MaxLength - fixed - len(rule.Separator); available < len(stack) {
+		if available <= 0 {
+			stack = ""
+		} else {
+			stack = strings.TrimRight(stack[:available], rule.Separator)
+		}
+	}
+	if stack == "" {
+		if available := rule.MaxLength - len(n.Prefix) - len(n.Suffix) - 2*len(rule.Separator); available < len(name) {
+			name = shortenNamePart(rule, name, available)
+		}
+	}
+
+	parts := []string{n.Prefix, name}
+	if stack != "" {
+		parts = append(parts, stack)
+	}
+	parts = append(parts, n.Suffix)
+	return strings.Join(parts, rule.Separator)
+}
+
+// Function - Lowercase a Name part and replace any characters the rule does not allow.
+func sanitizeNamePart(rule nameRule, part string) string {
+	part = strings.ToLower(part)
+	part = strings.ReplaceAll(part, "-", rule.Separator)
+	part = rule.Invalid.ReplaceAllString(part, rule.Separator)
+	return strings.Trim(part, rule.Separator)
+}
+
+// Function - Shorten a Name part to at most 'length' characters; The kept characters are followed by a 4 character hash of the full part.
+func shortenNamePart(rule nameRule, part string, length int) string {
+	hash := sha256.Sum256([]byte(part))
+	digest := hex.EncodeToString(hash[:])[:4]
+	keep := length - len(rule.Separator) - len(digest)
+	if keep <= 0 {
+		return digest[:length]
+	}
+	kept := strings.TrimRight(part[:keep], rule.Separator)
+	if kept == "" {
+		return digest
+	}
+	return kept + rule.Separator + digest
+}
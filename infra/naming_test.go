@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResourceNamerBuild(t *testing.T) {
+	names := &resourceNamer{Prefix: "gas", Stack: "dev", Suffix: "2967"}
+	tests := []struct {
+		name     string
+		build    func(string) string
+		input    string
+		expected string
+	}{
+		{"fits with stack", names.ServiceAccountId, "svc-gke-nodes", "gas-svc-gke-nodes-dev-2967"},
+		{"stack dropped", names.ServiceAccountId, "app-payments-process", "gas-app-payments-process-2967"},
+		{"compute name", names.ComputeName, "vpc", "gas-vpc-dev-2967"},
+		{"custom role separator", names.CustomRoleId, "iam-role-autoneg", "gas_iam_role_autoneg_dev_2967"},
+		{"invalid characters", names.ClusterName, "GKE us.central1", "gas-gke-us-central1-dev-2967"},
+		{"node pool", names.NodePoolName, "gke-us-central1-np-01", "gas-gke-us-central1-np-01-dev-2967"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.build(test.input); got != test.expected {
+				t.Errorf("got '%s', expected '%s'", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestResourceNamerBuildShortened(t *testing.T) {
+	names := &resourceNamer{Prefix: "gas", Stack: "production", Suffix: "2967"}
+	tests := []struct {
+		name      string
+		rule      nameRule
+		inputs    []string
+		maxLength int
+	}{
+		{"service accounts", nameRuleServiceAccount, []string{"app-payments-processor-east", "app-payments-processor-west"}, 30},
+		{"workload identity pools", nameRuleWorkloadIdentityPool, []string{"wip-gke-cluster-one-region-a", "wip-gke-cluster-one-region-b"}, 32},
+		{"clusters", nameRuleCluster, []string{"gke-northamerica-northeast1-primary-a", "gke-northamerica-northeast1-primary-b"}, 40},
+		{"node pools", nameRuleNodePool, []string{"gke-northamerica-northeast1-np-01", "gke-northamerica-northeast1-np-02"}, 40},
+		{"custom roles", nameRuleCustomRole, []string{strings.Repeat("role-", 12) + "a", strings.Repeat("role-", 12) + "b"}, 64},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			seen := map[string]string{}
+			for _, input := range test.inputs {
+				got := names.build(test.rule, input)
+				if len(got) > test.maxLength {
+					t.Errorf("'%s' is %d characters, longer than %d", got, len(got), test.maxLength)
+				}
+				if !strings.HasPrefix(got, "gas") || !strings.HasSuffix(got, "2967") {
+					t.Errorf("'%s' lost its prefix or suffix", got)
+				}
+				if other, ok := seen[got]; ok {
+					t.Errorf("'%s' and '%s' both build '%s'", other, input, got)
+				}
+				seen[got] = input
+				if again := names.build(test.rule, input); again != got {
+					t.Errorf("'%s' is not stable: '%s' then '%s'", input, got, again)
+				}
+			}
+		})
+	}
+}
+
+func TestShortenNamePart(t *testing.T) {
+	tests := []struct {
+		part   string
+		length int
+	}{
+		{"app-payments-processor-east", 20},
+		{"app-payments-processor-east", 6},
+		{"app-payments-processor-east", 3},
+		{"a-----------------------b", 8},
+	}
+	for _, test := range tests {
+		got := shortenNamePart(nameRuleServiceAccount, test.part, test.length)
+		if len(got) > test.length || got == "" {
+			t.Errorf("shortenNamePart('%s', %d) = '%s'", test.part, test.length, got)
+		}
+		if strings.HasPrefix(got, "-") || strings.HasSuffix(got, "-") {
+			t.Errorf("shortenNamePart('%s', %d) = '%s' has a dangling separator", test.part, test.length, got)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// Workload Scaling; Unset (zero) values fall back to the stack-wide setting, then the defaults.
+type scalingConfig struct {
+	MinReplicas          int               `json:"minReplicas"`
+	MaxReplicas          int               `json:"maxReplicas"`
+	TargetCPUUtilization int               `json:"targetCpuUtilization"`
+	Requests             map[string]string `json:"requests"`
+	Limits               map[string]string `json:"limits"`
+	// Pods kept available during voluntary disruptions; 0 disables the PodDisruptionBudget
+	PDBMinAvailable *int  `json:"pdbMinAvailable"`
+	ZoneSpread      *bool `json:"zoneSpread"`
+}
+
+// Regional Workload Scaling; Stack-wide scaling with per-region overrides (keyed by region)
+type regionalScalingConfig struct {
+	scalingConfig
+	Regions map[string]scalingConfig `json:"regions"`
+	// Workload name used in configuration messages
+	workload string
+}
+
+// Function - Read & Validate a Regional Workload Scaling configuration
+func loadRegionalScalingConfig(cfg *config.Config, key string, workload string, defaults scalingConfig) (*regionalScalingConfig, error) {
+	scaling := &regionalScalingConfig{workload: workload}
+	if err := cfg.GetObject(key, scaling); err != nil {
+		return nil, fmt.Errorf("[CONFIGURATION] - %s: %w", workload, err)
+	}
+	scaling.scalingConfig = scaling.scalingConfig.merge(defaults)
+	if err := scaling.scalingConfig.validate(workload, "stack"); err != nil {
+		return nil, err
+	}
+	for region := range scaling.Regions {
+		if !isCloudRegion(region) {
+			return nil, fmt.Errorf("[CONFIGURATION] - %s: scaling for unknown region '%s'", workload, region)
+		}
+		if err := scaling.forRegion(region).validate(workload, region); err != nil {
+			return nil, err
+		}
+	}
+	return scaling, nil
+}
+
+// Function - The Workload Scaling for a region; Region overrides layered on the stack-wide settings
+func (c *regionalScalingConfig) forRegion(region string) scalingConfig {
+	return c.Regions[region].merge(c.scalingConfig)
+}
+
+// Function - Fill any unset values from the given base
+func (s scalingConfig) merge(base scalingConfig) scalingConfig {
+	if s.MinReplicas == 0 {
+		s.MinReplicas = base.MinReplicas
+	}
+	if s.MaxReplicas == 0 {
+		s.MaxReplicas = base.MaxReplicas
+	}
+	if s.TargetCPUUtilization == 0 {
+		s.TargetCPUUtilization = base.TargetCPUUtilization
+	}
+	if s.PDBMinAvailable == nil {
+		s.PDBMinAvailable = base.PDBMinAvailable
+	}
+	if s.ZoneSpread == nil {
+		s.ZoneSpread = base.ZoneSpread
+	}
+	s.Requests = mergeStringMaps(base.Requests, s.Requests)
+	s.Limits = mergeStringMaps(base.Limits, s.Limits)
+	return s
+}
+
+// Function - Check the Workload Scaling is consistent
+func (s scalingConfig) validate(workload string, scope string) error {
+	if s.MinReplicas < 1 || s.MaxReplicas < s.MinReplicas {
+		return fmt.Errorf("[CONFIGURATION] - %s (%s): minReplicas (%d) must be at least 1 and no more than maxReplicas (%d)", workload, scope, s.MinReplicas, s.MaxReplicas)
+	}
+	if *s.PDBMinAvailable < 0 || (*s.PDBMinAvailable > 0 && *s.PDBMinAvailable >= s.MinReplicas) {
+		return fmt.Errorf("[CONFIGURATION] - %s (%s): pdbMinAvailable (%d) must be less than minReplicas (%d) so nodes can be drained; 0 disables the PodDisruptionBudget", workload, scope, *s.PDBMinAvailable, s.MinReplicas)
+	}
+	return nil
+}
+
+// Function - Merge String Maps; Keys in later maps override earlier ones
+func mergeStringMaps(maps ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, m := range maps {
+		for key, value := range m {
+			merged[key] = value
+		}
+	}
+	return merged
+}
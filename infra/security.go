@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes"
+	"github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/apiextensions"
+	k8s "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/core/v1"
+	metav1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/meta/v1"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// Istio Mesh Security Baseline; Applied to every regional cluster.
+type meshSecurityConfig struct {
+	// Mesh-wide mTLS Mode; 'STRICT' or 'PERMISSIVE' (while migrating workloads onto the mesh)
+	MtlsMode string
+	// Deny all traffic to application namespaces except what is explicitly allowed
+	DefaultDeny bool
+}
+
+// Function - Read & Validate the Istio Mesh Security configuration
+func loadMeshSecurityConfig(cfg *config.Config) (*meshSecurityConfig, error) {
+	security := &meshSecurityConfig{
+		MtlsMode:    cfg.Get("istioMtlsMode"),
+		DefaultDeny: true,
+	}
+	if security.MtlsMode == "" {
+		security.MtlsMode = "STRICT"
+	}
+	if security.MtlsMode != "STRICT" && security.MtlsMode != "PERMISSIVE" {
+		return nil, fmt.Errorf("[CONFIGURATION] - Istio mTLS Mode: '%s' must be 'STRICT' or 'PERMISSIVE'", security.MtlsMode)
+	}
+	defaultDeny, err := cfg.TryBool("istioDefaultDeny")
+	switch {
+	case err == nil:
+		security.DefaultDeny = defaultDeny
+	case !errors.Is(err, config.ErrMissingVar):
+		return nil, fmt.Errorf("[CONFIGURATION] - Istio Default Deny: '%s' must be 'true' or 'false'", cfg.Get("istioDefaultDeny"))
+	}
+	fmt.Printf("[CONFIGURATION] - Istio Security: mTLS Mode '%s'; Default Deny Authorization: %t.\n", security.MtlsMode, security.DefaultDeny)
+	return security, nil
+}
+
+// Function - Apply the Mesh-Wide PeerAuthentication and the Application Namespace AuthorizationPolicies to a cluster.
+// The Ingress Gateway runs in its own application namespace so it is explicitly allowed to receive traffic from the Load Balancer,
+// and every application namespace allows traffic from the Ingress Gateway.
+func createMeshSecurityPolicies(ctx *pulumi.Context, resourceNamePrefix string, region string, security *meshSecurityConfig, gatewayNamespace string, appNamespaces map[string]*k8s.Namespace, opts ...pulumi.ResourceOption) error {
+	// Mesh-Wide mTLS; Applied in the Istio root namespace
+	resourceName := fmt.Sprintf("%s-istio-peer-authentication-%s", resourceNamePrefix, region)
+	_, err := apiextensions.NewCustomResource(ctx, resourceName, &apiextensions.CustomResourceArgs{
+		ApiVersion: pulumi.String("security.istio.io/v1beta1"),
+		Kind:       pulumi.String("PeerAuthentication"),
+		Metadata: &metav1.ObjectMetaArgs{
+			Name:      pulumi.String("default"),
+			Namespace: pulumi.String("istio-system"),
+		},
+		OtherFields: kubernetes.UntypedArgs{
+			"spec": pulumi.Map{
+				"mtls": pulumi.Map{
+					"mode": pulumi.String(security.MtlsMode),
+				},
+			},
+		},
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	if !security.DefaultDeny {
+		return nil
+	}
+
+	// Allow the Ingress Gateway to receive traffic from the Global Load Balancer (and its Health Checks)
+	resourceName = fmt.Sprintf("%s-istio-authz-allow-ingress-gateway-%s", resourceNamePrefix, region)
+	_, err = newAuthorizationPolicy(ctx, resourceName, "allow-ingress-gateway", appNamespaces[gatewayNamespace].Metadata.Name().Elem(), pulumi.Map{
+		"selector": pulumi.Map{
+			"matchLabels": pulumi.StringMap{
+				"istio": pulumi.String("ingressgateway"),
+			},
+		},
+		"action": pulumi.String("ALLOW"),
+		"rules": pulumi.Array{
+			pulumi.Map{},
+		},
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	for _, namespace := range namespaceNames(appNamespaces) {
+		appNamespace := appNamespaces[namespace].Metadata.Name().Elem()
+		resourceNameSuffix := region
+		if namespace != gatewayNamespace {
+			resourceNameSuffix = fmt.Sprintf("%s-%s", namespace, region)
+		}
+
+		// Default Deny; An ALLOW policy with no rules matches no requests
+		resourceName = fmt.Sprintf("%s-istio-authz-deny-all-%s", resourceNamePrefix, resourceNameSuffix)
+		_, err = newAuthorizationPolicy(ctx, resourceName, "deny-all", appNamespace, pulumi.Map{}, opts...)
+		if err != nil {
+			return err
+		}
+
+		// Allow the Ingress Gateway to reach the Application Services
+		resourceName = fmt.Sprintf("%s-istio-authz-allow-from-ingress-gateway-%s", resourceNamePrefix, resourceNameSuffix)
+		_, err = newAuthorizationPolicy(ctx, resourceName, "allow-from-ingress-gateway", appNamespace, pulumi.Map{
+			"action": pulumi.String("ALLOW"),
+			"rules": pulumi.Array{
+				pulumi.Map{
+					"from": pulumi.Array{
+						pulumi.Map{
+							"source": pulumi.Map{
+								"principals": pulumi.StringArray{
+									pulumi.String(fmt.Sprintf("cluster.local/ns/%s/sa/istio-ingressgateway", gatewayNamespace)),
+								},
+							},
+						},
+					},
+				},
+			},
+		}, opts...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Function - Create an Istio AuthorizationPolicy
+func newAuthorizationPolicy(ctx *pulumi.Context, resourceName string, name string, namespace pulumi.StringInput, spec pulumi.Map, opts ...pulumi.ResourceOption) (*apiextensions.CustomResource, error) {
+	return apiextensions.NewCustomResource(ctx, resourceName, &apiextensions.CustomResourceArgs{
+		ApiVersion: pulumi.String("security.istio.io/v1beta1"),
+		Kind:       pulumi.String("AuthorizationPolicy"),
+		Metadata: &metav1.ObjectMetaArgs{
+			Name:      pulumi.String(name),
+			Namespace: namespace,
+		},
+		OtherFields: kubernetes.UntypedArgs{
+			"spec": spec,
+		},
+	}, opts...)
+}
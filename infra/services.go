@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v6/go/gcp/projects"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Google API's required by the VPC Network, Firewalls, Addresses & Load Balancers
+var ComputeServices = []string{
+	"compute.googleapis.com",
+}
+
+// Google API's required by the regional GKE Clusters
+var GKEServices = []string{
+	"container.googleapis.com",
+}
+
+// Google API's required by the Service Accounts, Custom Roles, Workload Identity Pool & Project IAM grants
+// (Project IAM Policies are updated through the Resource Manager API)
+var IAMServices = []string{
+	"iam.googleapis.com",
+	"cloudresourcemanager.googleapis.com",
+}
+
+// Google API's required to manage the DNS Records for the Domain
+var DNSServices = []string{
+	"dns.googleapis.com",
+}
+
+// The Google API's required by the enabled features; Each API is enabled once,
+// and resources depend only on the API's they use.
+type gcpServiceSet struct {
+	names    []string
+	services map[string]*projects.Service
+}
+
+// Function - Create an empty set of Google API's
+func newServiceSet() *gcpServiceSet {
+	return &gcpServiceSet{
+		services: map[string]*projects.Service{},
+	}
+}
+
+// Function - Declare the Google API's a feature requires
+func (s *gcpServiceSet) require(services ...string) {
+	for _, service := range services {
+		if _, ok := s.services[service]; ok {
+			continue
+		}
+		s.services[service] = nil
+		s.names = append(s.names, service)
+	}
+}
+
+// Function - Enable every required Google API on the Specified Project
+func (s *gcpServiceSet) enable(ctx *pulumi.Context, resourceNamePrefix string, gcpProjectId string) error {
+	for _, service := range s.names {
+		fmt.Printf("[CONFIGURATION] - Google API: %s will be enabled.\n", service)
+		resourceName := fmt.Sprintf("%s-project-service-%s", resourceNamePrefix, service)
+		gcpService, err := projects.NewService(ctx, resourceName, &projects.ServiceArgs{
+			DisableDependentServices: pulumi.Bool(true),
+			Project:                  pulumi.String(gcpProjectId),
+			Service:                  pulumi.String(service),
+			DisableOnDestroy:         pulumi.Bool(false),
+		})
+		if err != nil {
+			return err
+		}
+		s.services[service] = gcpService
+	}
+	return nil
+}
+
+// Function - The API Enablement Resources for the given Google API's; Used with pulumi.DependsOn
+func (s *gcpServiceSet) dependencies(services ...string) ([]pulumi.Resource, error) {
+	dependencies := []pulumi.Resource{}
+	for _, service := range services {
+		gcpService, ok := s.services[service]
+		if !ok || gcpService == nil {
+			return nil, fmt.Errorf("Google API '%s' is used before it was required & enabled", service)
+		}
+		dependencies = append(dependencies, gcpService)
+	}
+	return dependencies, nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// Application Traffic Policy; Rendered into the Istio DestinationRule & VirtualService of the 'app-team' chart.
+// Unset (zero) values fall back to the stack-wide setting, then the defaults.
+type trafficPolicyConfig struct {
+	// Prefer endpoints in the caller's zone, failing over to the other zones of the region
+	LocalityLoadBalancing *bool `json:"localityLoadBalancing"`
+	// Outlier Detection; Endpoints returning consecutive 5xx errors are ejected from the load balancing pool.
+	// 0 consecutive 5xx errors or a 0 max ejection percent turns ejection off.
+	Consecutive5xxErrors *int   `json:"consecutive5xxErrors"`
+	Interval             string `json:"interval"`
+	BaseEjectionTime     string `json:"baseEjectionTime"`
+	MaxEjectionPercent   *int   `json:"maxEjectionPercent"`
+	// Connection Pool Limits
+	MaxConnections     int `json:"maxConnections"`
+	MaxPendingRequests int `json:"maxPendingRequests"`
+	MaxRequests        int `json:"maxRequests"`
+	// Retries; 0 turns retries off
+	RetryAttempts *int   `json:"retryAttempts"`
+	PerTryTimeout string `json:"perTryTimeout"`
+	RetryOn       string `json:"retryOn"`
+}
+
+// Regional Application Traffic Policy; Stack-wide policy with per-region overrides (keyed by region)
+type regionalTrafficPolicyConfig struct {
+	trafficPolicyConfig
+	Regions map[string]trafficPolicyConfig `json:"regions"`
+}
+
+// Default Application Traffic Policy
+var appTrafficPolicyDefaults = trafficPolicyConfig{
+	LocalityLoadBalancing: pulumi.BoolRef(true),
+	Consecutive5xxErrors:  pulumi.IntRef(5),
+	Interval:              "10s",
+	BaseEjectionTime:      "30s",
+	MaxEjectionPercent:    pulumi.IntRef(50),
+	MaxConnections:        1024,
+	MaxPendingRequests:    1024,
+	MaxRequests:           1024,
+	RetryAttempts:         pulumi.IntRef(3),
+	PerTryTimeout:         "2s",
+	RetryOn:               "5xx,reset,connect-failure,refused-stream",
+}
+
+// Function - Read & Validate the Application Traffic Policy from the 'appTrafficPolicy' configuration
+func loadAppTrafficPolicyConfig(cfg *config.Config) (*regionalTrafficPolicyConfig, error) {
+	policy := &regionalTrafficPolicyConfig{}
+	if err := cfg.GetObject("appTrafficPolicy", policy); err != nil {
+		return nil, fmt.Errorf("[CONFIGURATION] - App Traffic Policy: %w", err)
+	}
+	policy.trafficPolicyConfig = policy.trafficPolicyConfig.merge(appTrafficPolicyDefaults)
+	if err := policy.trafficPolicyConfig.validate("stack"); err != nil {
+		return nil, err
+	}
+	for region := range policy.Regions {
+		if !isCloudRegion(region) {
+			return nil, fmt.Errorf("[CONFIGURATION] - App Traffic Policy: policy for unknown region '%s'", region)
+		}
+		if err := policy.forRegion(region).validate(region); err != nil {
+			return nil, err
+		}
+	}
+	fmt.Printf("[CONFIGURATION] - App Traffic Policy: Locality load balancing %t, eject after %d consecutive 5xx errors, %d retries.\n", *policy.LocalityLoadBalancing, *policy.Consecutive5xxErrors, *policy.RetryAttempts)
+	return policy, nil
+}
+
+// Function - The Application Traffic Policy for a region; Region overrides layered on the stack-wide policy
+func (c *regionalTrafficPolicyConfig) forRegion(region string) trafficPolicyConfig {
+	return c.Regions[region].merge(c.trafficPolicyConfig)
+}
+
+// Function - Fill any unset values from the given base
+func (p trafficPolicyConfig) merge(base trafficPolicyConfig) trafficPolicyConfig {
+	if p.LocalityLoadBalancing == nil {
+		p.LocalityLoadBalancing = base.LocalityLoadBalancing
+	}
+	if p.Consecutive5xxErrors == nil {
+		p.Consecutive5xxErrors = base.Consecutive5xxErrors
+	}
+	if p.Interval == "" {
+		p.Interval = base.Interval
+	}
+	if p.BaseEjectionTime == "" {
+		p.BaseEjectionTime = base.BaseEjectionTime
+	}
+	if p.MaxEjectionPercent == nil {
+		p.MaxEjectionPercent = base.MaxEjectionPercent
+	}
+	if p.MaxConnections == 0 {
+		p.MaxConnections = base.MaxConnections
+	}
+	if p.MaxPendingRequests == 0 {
+		p.MaxPendingRequests = base.MaxPendingRequests
+	}
+	if p.MaxRequests == 0 {
+		p.MaxRequests = base.MaxRequests
+	}
+	if p.RetryAttempts == nil {
+		p.RetryAttempts = base.RetryAttempts
+	}
+	if p.PerTryTimeout == "" {
+		p.PerTryTimeout = base.PerTryTimeout
+	}
+	if p.RetryOn == "" {
+		p.RetryOn = base.RetryOn
+	}
+	return p
+}
+
+// Function - Check the Application Traffic Policy is consistent
+func (p trafficPolicyConfig) validate(scope string) error {
+	for name, value := range map[string]string{"interval": p.Interval, "baseEjectionTime": p.BaseEjectionTime, "perTryTimeout": p.PerTryTimeout} {
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("[CONFIGURATION] - App Traffic Policy (%s): %s '%s' must be a duration, eg. '10s'", scope, name, value)
+		}
+	}
+	if *p.MaxEjectionPercent < 0 || *p.MaxEjectionPercent > 100 {
+		return fmt.Errorf("[CONFIGURATION] - App Traffic Policy (%s): maxEjectionPercent (%d) must be between 0 and 100", scope, *p.MaxEjectionPercent)
+	}
+	if *p.Consecutive5xxErrors < 0 {
+		return fmt.Errorf("[CONFIGURATION] - App Traffic Policy (%s): consecutive5xxErrors (%d) must be at least 0; 0 turns ejection off", scope, *p.Consecutive5xxErrors)
+	}
+	if p.MaxConnections < 1 || p.MaxPendingRequests < 1 || p.MaxRequests < 1 {
+		return fmt.Errorf("[CONFIGURATION] - App Traffic Policy (%s): connection & request limits must be at least 1", scope)
+	}
+	if *p.RetryAttempts < 0 {
+		return fmt.Errorf("[CONFIGURATION] - App Traffic Policy (%s): retryAttempts (%d) must be at least 0; 0 turns retries off", scope, *p.RetryAttempts)
+	}
+	return nil
+}
+
+// Function - Helm values for the DestinationRule traffic policy & VirtualService retries of the 'app-team' chart
+func appTrafficPolicyHelmValues(p trafficPolicyConfig) map[string]interface{} {
+	// Retries turned off only set the attempts; The chart renders 'retries: {attempts: 0}'
+	retries := map[string]interface{}{
+		"attempts": *p.RetryAttempts,
+	}
+	if *p.RetryAttempts > 0 {
+		retries["perTryTimeout"] = p.PerTryTimeout
+		retries["retryOn"] = p.RetryOn
+	}
+	return map[string]interface{}{
+		"trafficPolicy": map[string]interface{}{
+			"localityLoadBalancing": map[string]interface{}{
+				"enabled": *p.LocalityLoadBalancing,
+			},
+			"outlierDetection": map[string]interface{}{
+				"consecutive5xxErrors": *p.Consecutive5xxErrors,
+				"interval":             p.Interval,
+				"baseEjectionTime":     p.BaseEjectionTime,
+				"maxEjectionPercent":   *p.MaxEjectionPercent,
+			},
+			"connectionPool": map[string]interface{}{
+				"tcp": map[string]interface{}{
+					"maxConnections": p.MaxConnections,
+				},
+				"http": map[string]interface{}{
+					"http1MaxPendingRequests": p.MaxPendingRequests,
+					"http2MaxRequests":        p.MaxRequests,
+				},
+			},
+			"retries": retries,
+		},
+	}
+}